@@ -0,0 +1,79 @@
+package main
+
+import (
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/config"
+)
+
+// loadConfig builds the application config. It currently falls back to
+// hardcoded defaults; env var parsing will be wired in as config.Config
+// grows.
+func loadConfig() (*config.Config, error) {
+	return &config.Config{
+		Server: config.Server{Addr: ":8080", EgressPerConnKbps: 0, EgressGlobalKbps: 0},
+		Job: config.Job{
+			MinWorkers:           1,
+			MaxWorkers:           4,
+			QueueDepth:           256,
+			ProfileConcurrency:   1,
+			OutputDir:            "./data/downloads",
+			RetryMaxAttempts:     3,
+			RetryBackoffBase:     5 * time.Second,
+			DrainTimeout:         30 * time.Second,
+			QueuePersistPath:     "./data/queue.json",
+			MaxRateKbps:          0,
+			PlaylistArchiveDir:   "./data/playlists",
+			MaxQueuedPerIdentity: 0,
+			WorkerStuckGrace:     5 * time.Minute,
+		},
+		Retention: config.Retention{
+			FileTTL:     7 * 24 * time.Hour,
+			MetadataTTL: 30 * 24 * time.Hour,
+		},
+		Watch: config.Watch{
+			Interval: 30 * time.Second,
+		},
+		Archive: config.Archive{
+			EnqueuePace: 2 * time.Second,
+		},
+		Thumbnail: config.Thumbnail{
+			Interval:   10 * time.Second,
+			TileWidth:  160,
+			TileHeight: 90,
+			Columns:    10,
+		},
+		Transcription: config.Transcription{
+			DepDir:      "./data/deps",
+			Model:       "base",
+			QueueDepth:  32,
+			Concurrency: 1,
+		},
+		Webhook: config.Webhook{
+			MaxAttempts: 3,
+			BackoffBase: 2 * time.Second,
+		},
+		Scheduler: config.Scheduler{
+			CheckInterval: time.Minute,
+			ArchiveDir:    "./data/schedules",
+		},
+		Support: config.Support{
+			MaxFailedJobs: 20,
+		},
+		Logging: config.Logging{
+			SampleRates:        []string{"progress=50"},
+			DefaultSampleEvery: 1,
+		},
+		RateLimit: config.RateLimit{
+			RequestsPerMinute:      0,
+			Burst:                  10,
+			MaxConcurrentDownloads: 0,
+		},
+		Privacy: config.Privacy{
+			RedactURLs: false,
+		},
+		Auth: config.Auth{
+			ReloadInterval: 30 * time.Second,
+		},
+	}, nil
+}
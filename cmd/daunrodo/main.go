@@ -0,0 +1,235 @@
+// Command daunrodo runs the daunrodo API daemon.
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"syscall"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/crawler"
+	"github.com/evan-sm/daunrodo/internal/crawler/opengraph"
+	"github.com/evan-sm/daunrodo/internal/crawler/reddit"
+	"github.com/evan-sm/daunrodo/internal/crawler/ytdlp"
+	v1 "github.com/evan-sm/daunrodo/internal/delivery/http/v1"
+	v2 "github.com/evan-sm/daunrodo/internal/delivery/http/v2"
+	"github.com/evan-sm/daunrodo/internal/depmanager"
+	"github.com/evan-sm/daunrodo/internal/enrich"
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/httpserver"
+	"github.com/evan-sm/daunrodo/internal/middleware"
+	"github.com/evan-sm/daunrodo/internal/preset"
+	"github.com/evan-sm/daunrodo/internal/repository/memory"
+	"github.com/evan-sm/daunrodo/internal/service"
+	"github.com/evan-sm/daunrodo/internal/worker"
+	"github.com/evan-sm/daunrodo/pkg/clock"
+	"github.com/evan-sm/daunrodo/pkg/logger"
+	"github.com/evan-sm/daunrodo/pkg/memguard"
+	"github.com/evan-sm/daunrodo/pkg/ratelimit"
+	"github.com/evan-sm/daunrodo/pkg/retry"
+	"github.com/evan-sm/daunrodo/pkg/safehttp"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		if err := runSimulate(os.Args[2:]); err != nil {
+			slog.New(slog.NewJSONHandler(os.Stderr, nil)).Error("simulate", "error", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	log := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Error("load config", "error", err)
+		os.Exit(1)
+	}
+
+	ctxHandler := logger.NewContextHandler(log.Handler())
+	sampling := logger.NewSamplingHandler(ctxHandler, logger.ParseSampleRates(cfg.Logging.SampleRates), cfg.Logging.DefaultSampleEvery)
+	log = slog.New(sampling)
+
+	if cfg.Runtime.GOGC > 0 {
+		debug.SetGCPercent(cfg.Runtime.GOGC)
+	}
+
+	if cfg.Runtime.MemoryLimitBytes > 0 {
+		debug.SetMemoryLimit(cfg.Runtime.MemoryLimitBytes)
+	}
+
+	// ballast is a dead allocation kept alive for the life of the process
+	// (main's stack frame never returns until shutdown) purely to raise the
+	// heap's baseline size; see config.Runtime.BallastBytes.
+	var ballast []byte
+	if cfg.Runtime.BallastBytes > 0 {
+		ballast = make([]byte, cfg.Runtime.BallastBytes)
+	}
+	defer runtime.KeepAlive(ballast)
+
+	jobRepo := memory.NewJobRepository()
+	pubRepo := memory.NewPublicationRepository()
+	groupRepo := memory.NewGroupRepository()
+	scheduleRepo := memory.NewScheduleRepository()
+	keyDefaultsRepo := memory.NewKeyDefaultsRepository()
+
+	prober := ytdlp.NewProber(cfg.Archive.YtdlpPath)
+
+	jobSvc := service.NewJobService(jobRepo, groupRepo, prober, preset.DefaultAutoRules(), cfg.Job.PlaylistArchiveDir)
+
+	retryPolicy := retry.Policy{MaxAttempts: cfg.Job.RetryMaxAttempts, Base: cfg.Job.RetryBackoffBase}
+	runner := service.NewYtDlpRunner(cfg.Job.YtdlpPath, cfg.Job.OutputDir, preset.Default(), jobSvc, groupRepo, retryPolicy, cfg.Job.FaultInjectionEnabled, log, cfg.Privacy, cfg.Job.MaxRateKbps, service.ParsePlatformPresetOverrides(cfg.Job.PlatformPresetOverrides), cfg.Job.StallTimeout, service.ParseDirectoryLayouts(cfg.Job.DirectoryLayouts))
+	domainLimiter := worker.NewDomainLimiter(worker.ParseDomainConcurrency(cfg.Job.DomainConcurrency))
+	memGuard := memguard.New(cfg.Runtime.PauseDequeueThresholdBytes)
+
+	var recycleStuckWorker func(job *entity.Job)
+	if cfg.Job.WorkerRecycleEnabled {
+		recycleStuckWorker = func(job *entity.Job) {
+			if err := runner.Kill(job.ID); err != nil {
+				log.Warn("worker watchdog: recycle failed", "job_id", job.ID, "error", err)
+			}
+		}
+	}
+
+	pool := worker.New(runner, cfg.Job.QueueDepth, cfg.Job.MinWorkers, cfg.Job.MaxWorkers, cfg.Job.ProfileConcurrency, domainLimiter, cfg.Job.MaxQueuedPerIdentity, memGuard, log, cfg.Job.WorkerStuckTimeout, cfg.Job.WorkerStuckGrace, recycleStuckWorker)
+	jobSvc.SetPool(pool)
+	jobSvc.SetRunner(runner)
+	jobSvc.SetNotifier(service.NewWebhookNotifier(cfg.Webhook, log))
+	jobSvc.SetDefaultPresetsByExtractor(service.ParseDefaultPresetsByExtractor(cfg.Job.DefaultPresetsByExtractor))
+	jobSvc.SetMetadataProber(crawler.FallbackProber{
+		Primary:  prober,
+		Fallback: opengraph.NewProber(safehttp.New()),
+	})
+	jobSvc.SetRedditResolver(reddit.NewResolver(safehttp.New()))
+	keyDefaultsSvc := service.NewKeyDefaultsService(keyDefaultsRepo)
+	jobSvc.SetKeyDefaults(keyDefaultsSvc)
+	jobSvc.SetMaxPlaylistItems(cfg.Job.MaxPlaylistItems)
+
+	if cfg.Job.ActiveHours != "" {
+		if window, err := service.ParseActiveHours(cfg.Job.ActiveHours); err != nil {
+			log.Error("parse active hours window", "error", err)
+		} else {
+			jobSvc.SetActiveHours(window)
+		}
+	}
+
+	if persisted, err := worker.LoadQueue(cfg.Job.QueuePersistPath); err != nil {
+		log.Error("load persisted queue", "error", err)
+	} else if len(persisted) > 0 {
+		jobSvc.RequeuePersisted(context.Background(), persisted)
+		log.Info("requeued persisted jobs", "count", len(persisted))
+	}
+
+	hub := service.NewHub()
+	jobSvc.SetHub(hub)
+
+	pubSvc := service.NewPublicationService(pubRepo, jobSvc, cfg.Retention, clock.New(), log)
+	pubSvc.SetEnricher(enrich.NewService(cfg.PlatformAPIs))
+	pubSvc.SetThumbnailConfig(cfg.Thumbnail)
+	importSvc := service.NewImportService(jobSvc, groupRepo)
+	archiveSvc := service.NewArchiveService(jobSvc, groupRepo, prober, cfg.Archive.EnqueuePace)
+	schedulerSvc := service.NewSchedulerService(scheduleRepo, jobSvc, cfg.Scheduler.ArchiveDir, log)
+	supportSvc := service.NewSupportBundleService(*cfg, jobRepo, pool, cfg.Transcription.DepDir, cfg.Support.MaxFailedJobs)
+	purgeSvc := service.NewPurgeService(jobRepo, pubRepo)
+
+	authSvc := service.NewAuthService()
+	if cfg.Auth.KeysFile != "" {
+		if err := authSvc.Load(cfg.Auth.KeysFile); err != nil {
+			log.Error("load auth keys file", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	deps := depmanager.NewManager(cfg.Transcription.DepDir, log)
+	_ = service.NewTranscriptionService(pubRepo, deps, cfg.Transcription.Model, cfg.Transcription.QueueDepth, cfg.Transcription.Concurrency, log)
+	_ = service.NewTranslationService(cfg.Translation.Endpoint, cfg.Translation.Command)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go pubSvc.RunRetentionLoop(ctx, time.Hour)
+	go schedulerSvc.Run(ctx, cfg.Scheduler.CheckInterval)
+
+	if cfg.Job.LibraryViewDir != "" {
+		libraryView := service.NewLibraryViewService(cfg.Job.LibraryViewDir, cfg.Job.OutputDir, jobSvc)
+		go libraryView.RunSyncLoop(ctx, cfg.Job.LibraryViewSyncInterval)
+	}
+
+	if cfg.Watch.Dir != "" {
+		go importSvc.WatchFolder(ctx, cfg.Watch.Dir, cfg.Watch.Interval, log)
+	}
+
+	if cfg.Auth.KeysFile != "" {
+		go authSvc.WatchFile(ctx, cfg.Auth.KeysFile, cfg.Auth.ReloadInterval, log)
+	}
+
+	enqueueLimiter := ratelimit.New(cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.Burst, clock.New())
+
+	mux := http.NewServeMux()
+	var globalEgress *ratelimit.ByteBucket
+	if cfg.Server.EgressGlobalKbps > 0 {
+		rate := cfg.Server.EgressGlobalKbps * 1000 / 8
+		globalEgress = ratelimit.NewByteBucket(rate, rate, clock.New())
+	}
+
+	files := v1.FileServer{
+		OutputDir:         cfg.Job.OutputDir,
+		Header:            cfg.Server.SendfileHeader,
+		Root:              cfg.Server.SendfileRoot,
+		EgressPerConnKbps: cfg.Server.EgressPerConnKbps,
+		EgressGlobal:      globalEgress,
+	}
+
+	v1Mux := http.NewServeMux()
+	downloadLimit := middleware.ConcurrencyLimit(cfg.RateLimit.MaxConcurrentDownloads)
+
+	v1Handler := v1.NewHandler(jobSvc, pubSvc, importSvc, archiveSvc, schedulerSvc, supportSvc, purgeSvc, files, hub, middleware.RateLimit(enqueueLimiter), downloadLimit, middleware.WriteTimeout(cfg.Server.FileWriteTimeout), cfg.Server.EnqueueViaQueryEnabled, keyDefaultsSvc, authSvc, cfg.Webhook.AllowPrivateHosts)
+	v1Handler.Register(v1Mux, cfg.Server.BasePath+"/v1")
+	mux.Handle(cfg.Server.BasePath+"/v1/", middleware.Deprecated(cfg.Server.V1Sunset)(v1Mux))
+	mux.HandleFunc("GET "+cfg.Server.BasePath+"/manifest.json", v1Handler.Manifest)
+	mux.HandleFunc("GET "+cfg.Server.BasePath+"/", v1Handler.WebUI)
+
+	v2.NewHandler(jobSvc).Register(mux, cfg.Server.BasePath+"/v2")
+
+	trustedProxies := middleware.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	handler := middleware.Compress(middleware.RealIP(trustedProxies)(middleware.RequestID(mux)))
+
+	srv := httpserver.New(cfg.Server.Addr, handler, httpserver.Options{
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+	})
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error("shutdown", "error", err)
+		}
+
+		unfinished := jobSvc.ShutdownPool(context.Background(), cfg.Job.DrainTimeout)
+		if err := worker.SaveQueue(cfg.Job.QueuePersistPath, unfinished); err != nil {
+			log.Error("persist queue", "error", err)
+		} else if len(unfinished) > 0 {
+			log.Info("persisted unfinished queue", "count", len(unfinished))
+		}
+	}()
+
+	log.Info("starting daunrodo", "addr", cfg.Server.Addr)
+
+	if err := srv.Start(); err != nil {
+		log.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
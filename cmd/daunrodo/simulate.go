@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/evan-sm/daunrodo/internal/simulate"
+)
+
+// runSimulate implements `daunrodo simulate`: loads a profile file and
+// drives the worker pool against simulate.MockRunner, printing a
+// throughput/latency report instead of starting the API server. args is
+// os.Args[2:], the flags following the "simulate" subcommand itself.
+func runSimulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	profilePath := fs.String("profile", "", "path to a simulation profile file, e.g. profiles/mixed.yaml")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *profilePath == "" {
+		return fmt.Errorf("simulate: -profile is required")
+	}
+
+	profile, err := simulate.LoadProfile(*profilePath)
+	if err != nil {
+		return err
+	}
+
+	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+	report, err := simulate.Run(context.Background(), profile, log)
+	if err != nil {
+		return fmt.Errorf("simulate: %w", err)
+	}
+
+	fmt.Print(report.String())
+
+	return nil
+}
@@ -0,0 +1,30 @@
+// Command daunrodoctl is a companion CLI for operating a daunrodo
+// deployment from outside the process, e.g. post-deploy smoke checks.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: daunrodoctl <command> [flags]")
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "smoke":
+		err = runSmoke(os.Args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "daunrodoctl: unknown command %q\n", os.Args[1])
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "daunrodoctl:", err)
+		os.Exit(1)
+	}
+}
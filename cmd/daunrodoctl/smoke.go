@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// smokeTestURL is yt-dlp's own canonical test fixture video: tiny, stable
+// and maintained specifically to be safe to re-download repeatedly in
+// automation, so the smoke test doesn't hammer an arbitrary real-world URL
+// on every deploy.
+const smokeTestURL = "https://www.youtube.com/watch?v=BaW_jenozKc"
+
+const (
+	smokePollInterval = 2 * time.Second
+	smokePollTimeout  = 2 * time.Minute
+)
+
+// runSmoke implements `daunrodoctl smoke`: enqueues a job against
+// smokeTestURL, polls it to completion, downloads its resulting file,
+// enqueues and cancels a second job, and checks the admin queue
+// introspection endpoint, printing a pass/fail line per step. Meant as a
+// post-deploy gate: a non-nil error here should fail the deploy.
+func runSmoke(args []string) error {
+	fs := flag.NewFlagSet("smoke", flag.ExitOnError)
+	baseURL := fs.String("url", "http://localhost:8080", "base URL of the running daunrodo instance")
+	apiKey := fs.String("api-key", "", "X-Daunrodo-Api-Key header to send, if the instance has auth configured")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c := &smokeClient{
+		base:   strings.TrimRight(*baseURL, "/"),
+		apiKey: *apiKey,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+
+	var failed []string
+
+	report := func(step string, err error) {
+		if err != nil {
+			fmt.Printf("FAIL  %-14s %v\n", step, err)
+			failed = append(failed, step)
+
+			return
+		}
+
+		fmt.Printf("PASS  %-14s\n", step)
+	}
+
+	job, err := c.enqueue(smokeTestURL)
+	report("enqueue", err)
+
+	if err == nil {
+		done, pollErr := c.pollUntilDone(job.ID)
+		report("poll", pollErr)
+
+		if pollErr == nil {
+			report("file-download", c.downloadFile(done.ID))
+		}
+	}
+
+	cancelJob, cancelEnqueueErr := c.enqueue(smokeTestURL)
+	if cancelEnqueueErr == nil {
+		cancelEnqueueErr = c.cancel(cancelJob.ID)
+	}
+	report("cancel", cancelEnqueueErr)
+
+	report("metrics", c.checkQueueStats())
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of 5 checks failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	fmt.Println("all smoke checks passed")
+
+	return nil
+}
+
+// smokeClient is a minimal hand-rolled HTTP client for the v1 API, just
+// enough of it for runSmoke; a full typed client isn't worth building for a
+// single CLI command.
+type smokeClient struct {
+	base   string
+	apiKey string
+	http   *http.Client
+}
+
+// smokeJob mirrors the subset of entity.Job's (untagged, so
+// capitalized-field) JSON that runSmoke needs.
+type smokeJob struct {
+	ID     string
+	Status string
+	Error  string
+}
+
+// smokePublication mirrors the subset of entity.Publication's JSON that
+// runSmoke needs to build a file URL.
+type smokePublication struct {
+	UUID string
+}
+
+func (c *smokeClient) do(method, path string, body any, out any) (*http.Response, error) {
+	var reqBody io.Reader
+
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, c.base+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.apiKey != "" {
+		req.Header.Set("X-Daunrodo-Api-Key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return resp, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp, fmt.Errorf("%s %s: decode response: %w", method, path, err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (c *smokeClient) enqueue(url string) (*smokeJob, error) {
+	var job smokeJob
+	if _, err := c.do(http.MethodPost, "/v1/jobs", map[string]string{"url": url}, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// pollUntilDone polls GET /v1/jobs/{id} until it reports JobStatusDone,
+// JobStatusError or JobStatusCancelled, or smokePollTimeout elapses.
+func (c *smokeClient) pollUntilDone(id string) (*smokeJob, error) {
+	deadline := time.Now().Add(smokePollTimeout)
+
+	for {
+		var job smokeJob
+		if _, err := c.do(http.MethodGet, "/v1/jobs/"+id, nil, &job); err != nil {
+			return nil, err
+		}
+
+		switch job.Status {
+		case "done":
+			return &job, nil
+		case "error":
+			return nil, fmt.Errorf("job %s finished with error: %s", id, job.Error)
+		case "cancelled":
+			return nil, fmt.Errorf("job %s was cancelled", id)
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("job %s still %s after %s", id, job.Status, smokePollTimeout)
+		}
+
+		time.Sleep(smokePollInterval)
+	}
+}
+
+// downloadFile resolves jobID's publication and fetches a handful of bytes
+// of its file, just enough to confirm the download route actually serves
+// content rather than erroring.
+func (c *smokeClient) downloadFile(jobID string) error {
+	var pub smokePublication
+	if _, err := c.do(http.MethodGet, "/v1/jobs/"+jobID+"/publication", nil, &pub); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.base+"/v1/files/"+pub.UUID, nil)
+	if err != nil {
+		return err
+	}
+
+	if c.apiKey != "" {
+		req.Header.Set("X-Daunrodo-Api-Key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GET /v1/files/%s: %s: %s", pub.UUID, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	buf := make([]byte, 1024)
+	if _, err := resp.Body.Read(buf); err != nil && err != io.EOF {
+		return fmt.Errorf("read file bytes: %w", err)
+	}
+
+	return nil
+}
+
+func (c *smokeClient) cancel(jobID string) error {
+	_, err := c.do(http.MethodPost, "/v1/jobs/"+jobID+"/cancel", nil, nil)
+
+	return err
+}
+
+// checkQueueStats hits the admin queue introspection endpoint, the closest
+// thing this deployment has to a metrics endpoint (see GET
+// /v1/admin/queue), confirming it's reachable and returns valid JSON.
+func (c *smokeClient) checkQueueStats() error {
+	var stats map[string]any
+
+	_, err := c.do(http.MethodGet, "/v1/admin/queue", nil, &stats)
+
+	return err
+}
@@ -0,0 +1,405 @@
+package config
+
+import "time"
+
+// Config is the root application configuration, assembled from environment
+// variables by the caller (see cmd/daunrodo).
+type Config struct {
+	Server        Server
+	Job           Job
+	Retention     Retention
+	Watch         Watch
+	Archive       Archive
+	Thumbnail     Thumbnail
+	Transcription Transcription
+	Translation   Translation
+	Webhook       Webhook
+	Scheduler     Scheduler
+	Support       Support
+	Logging       Logging
+	RateLimit     RateLimit
+	Privacy       Privacy
+	Runtime       Runtime
+	Auth          Auth
+	PlatformAPIs  PlatformAPIs
+}
+
+// Runtime tunes the Go runtime's own memory behavior for large-throughput
+// deployments, where many concurrent downloader stdout parses and file
+// copies can spike heap usage faster than the garbage collector reclaims it.
+type Runtime struct {
+	// GOGC sets the garbage collector's target heap growth percentage (see
+	// debug.SetGCPercent); 0 leaves the runtime default (100) in place.
+	GOGC int `env:"RUNTIME_GOGC" envDefault:"0"`
+	// MemoryLimitBytes sets a soft cap on total heap usage (see
+	// debug.SetMemoryLimit): the GC works harder as usage approaches it
+	// instead of waiting for GOGC's growth target alone. 0 leaves no limit
+	// set.
+	MemoryLimitBytes int64 `env:"RUNTIME_MEMORY_LIMIT_BYTES" envDefault:"0"`
+	// BallastBytes allocates and retains a dead byte slice of this size at
+	// startup, raising the heap's baseline size so GOGC's percentage-based
+	// growth target triggers less often under the same live-object count.
+	// Mostly superseded by MemoryLimitBytes but still useful paired with a
+	// GOGC-only deployment. 0 disables it.
+	BallastBytes int64 `env:"RUNTIME_BALLAST_BYTES" envDefault:"0"`
+	// PauseDequeueThresholdBytes, if set, pauses the worker pool from
+	// pulling more jobs off its queue once heap usage crosses it, letting
+	// already-running jobs finish and the GC catch up instead of risking an
+	// OOM kill under a burst of large stdout parses and file copies. 0
+	// disables the guard. See pkg/memguard.
+	PauseDequeueThresholdBytes int64 `env:"RUNTIME_PAUSE_DEQUEUE_THRESHOLD_BYTES" envDefault:"0"`
+}
+
+// Server holds HTTP listener settings.
+type Server struct {
+	Addr string `env:"SERVER_ADDR" envDefault:":8080"`
+	// BasePath prefixes every route, e.g. "/daunrodo", for installs behind
+	// a path-routing ingress. Empty serves the API at the root.
+	BasePath string `env:"SERVER_BASE_PATH"`
+	// TrustedProxies lists the CIDRs of reverse proxies allowed to set
+	// X-Forwarded-For; requests from any other source have the header
+	// ignored so a client can't spoof its own IP.
+	TrustedProxies []string `env:"SERVER_TRUSTED_PROXIES"`
+	// SendfileHeader, when set ("X-Accel-Redirect" for nginx,
+	// "X-Sendfile" for Apache/Caddy's mod_xsendfile), makes file-serving
+	// routes respond with that header pointing at SendfileRoot instead of
+	// streaming the file through Go, offloading the I/O to the proxy.
+	SendfileHeader string `env:"SERVER_SENDFILE_HEADER"`
+	// SendfileRoot is the internal location the proxy maps to the on-disk
+	// download directory, e.g. "/internal-files".
+	SendfileRoot string `env:"SERVER_SENDFILE_ROOT"`
+	// V1Sunset, if set, is sent as the Sunset header (an HTTP-date) on
+	// every /v1 response, alongside the always-present Deprecation
+	// header, once a retirement date for v1 in favor of /v2 is decided.
+	V1Sunset string `env:"SERVER_V1_SUNSET"`
+	// EgressPerConnKbps caps each individual file download's own
+	// throughput in kilobits/sec; 0 means unlimited. Only applies to files
+	// streamed through Go, not to proxy-offloaded (SendfileHeader) serving.
+	EgressPerConnKbps int `env:"SERVER_EGRESS_PER_CONN_KBPS" envDefault:"0"`
+	// EgressGlobalKbps caps aggregate throughput across every concurrent
+	// file download, on top of any per-connection cap; 0 means unlimited.
+	EgressGlobalKbps int `env:"SERVER_EGRESS_GLOBAL_KBPS" envDefault:"0"`
+	// EnqueueViaQueryEnabled exposes GET /v1/jobs/enqueue, accepting
+	// enqueue parameters as query-string values instead of a JSON body,
+	// for simple webhook sources and mobile share-sheet shortcuts that can
+	// only emit a GET request. Off by default since query strings tend to
+	// end up in proxy and server access logs. POST /v1/jobs always also
+	// accepts application/x-www-form-urlencoded bodies, regardless of
+	// this flag.
+	EnqueueViaQueryEnabled bool `env:"SERVER_ENQUEUE_VIA_QUERY_ENABLED" envDefault:"false"`
+	// ReadTimeout, WriteTimeout and IdleTimeout configure the matching
+	// fields on http.Server; see httpserver.New. WriteTimeout applies to
+	// every connection by default, including file download routes, which
+	// extend it per-request via middleware.WriteTimeout instead of
+	// needing a longer global value.
+	ReadTimeout time.Duration `env:"SERVER_READ_TIMEOUT" envDefault:"5s"`
+	WriteTimeout time.Duration `env:"SERVER_WRITE_TIMEOUT" envDefault:"10s"`
+	IdleTimeout time.Duration `env:"SERVER_IDLE_TIMEOUT" envDefault:"60s"`
+	// ReadHeaderTimeout bounds how long a client may take sending request
+	// headers, independent of ReadTimeout's cap on the full request.
+	ReadHeaderTimeout time.Duration `env:"SERVER_READ_HEADER_TIMEOUT" envDefault:"5s"`
+	// FileWriteTimeout overrides WriteTimeout on file download routes (see
+	// v1.FileServer), long enough that a slow client on a large file
+	// doesn't get cut off mid-download; see middleware.WriteTimeout.
+	FileWriteTimeout time.Duration `env:"SERVER_FILE_WRITE_TIMEOUT" envDefault:"1h"`
+}
+
+// Job holds worker pool and download job defaults.
+type Job struct {
+	// MinWorkers run at all times; MaxWorkers is the ceiling the pool scales
+	// up to while a backlog persists, scaling back down to MinWorkers once
+	// the queue drains. See worker.New.
+	MinWorkers int `env:"JOB_MIN_WORKERS" envDefault:"1"`
+	MaxWorkers int `env:"JOB_MAX_WORKERS" envDefault:"4"`
+	QueueDepth int `env:"JOB_QUEUE_DEPTH" envDefault:"256"`
+	// ProfileConcurrency caps how many jobs sharing a cookie/account
+	// profile may run at once (<=0 for unlimited).
+	ProfileConcurrency int    `env:"JOB_PROFILE_CONCURRENCY" envDefault:"1"`
+	YtdlpPath          string `env:"JOB_YTDLP_PATH"`
+	OutputDir          string `env:"JOB_OUTPUT_DIR" envDefault:"./data/downloads"`
+	// RetryMaxAttempts bounds how many times a failing job is tried in
+	// total (1 means no retries). RetryBackoffBase is the delay before the
+	// 2nd attempt, doubling on each subsequent one.
+	RetryMaxAttempts int           `env:"JOB_RETRY_MAX_ATTEMPTS" envDefault:"3"`
+	RetryBackoffBase time.Duration `env:"JOB_RETRY_BACKOFF_BASE" envDefault:"5s"`
+	// StallTimeout kills a job's yt-dlp process if its progress output goes
+	// silent for this long (e.g. stuck retrying a single fragment), letting
+	// RetryMaxAttempts pick it back up instead of waiting out the job's full
+	// timeout. <=0 disables the watchdog. See YtDlpRunner.watchStall.
+	StallTimeout time.Duration `env:"JOB_STALL_TIMEOUT" envDefault:"0"`
+	// DomainConcurrency lists "domain=limit" pairs, e.g.
+	// "youtube.com=1,instagram.com=3", capping concurrent jobs per source
+	// domain to avoid tripping a site's rate limiting. A domain not listed
+	// is unlimited.
+	DomainConcurrency []string `env:"JOB_DOMAIN_CONCURRENCY"`
+	// FaultInjectionEnabled gates entity.Job.FaultMode: with this off (the
+	// production default), a job's requested fault mode is ignored. Meant
+	// for staging environments doing end-to-end resilience testing.
+	FaultInjectionEnabled bool `env:"JOB_FAULT_INJECTION_ENABLED" envDefault:"false"`
+	// DrainTimeout bounds how long a graceful shutdown waits for
+	// already-running jobs to finish before giving up on them.
+	DrainTimeout time.Duration `env:"JOB_DRAIN_TIMEOUT" envDefault:"30s"`
+	// QueuePersistPath is where jobs still waiting in the queue at shutdown
+	// are written so they can be requeued on the next startup; empty
+	// disables this and abandons them, as before.
+	QueuePersistPath string `env:"JOB_QUEUE_PERSIST_PATH" envDefault:"./data/queue.json"`
+	// MaxRateKbps caps download bandwidth in kilobits/sec, passed to yt-dlp
+	// as --limit-rate. 0 means unlimited. A job may set a lower cap of its
+	// own at enqueue time; see entity.Job.MaxRateKbps.
+	MaxRateKbps int `env:"JOB_MAX_RATE_KBPS" envDefault:"0"`
+	// ActiveHours, if set (e.g. "01:00-07:00"), restricts new jobs to
+	// starting only inside that daily local-time window; a window where
+	// the start is after the end wraps past midnight (e.g. "22:00-06:00").
+	// Jobs enqueued outside it sit as JobStatusScheduled until it opens.
+	// Empty means no restriction. See service.ParseActiveHours.
+	ActiveHours string `env:"JOB_ACTIVE_HOURS"`
+	// PlatformPresetOverrides lists "domain=format" pairs (domain as
+	// worker.Domain would extract it, e.g.
+	// "instagram.com=bestvideo+bestaudio/best") applied on top of a job's
+	// selected preset's format at job start, for platforms that need a
+	// different format selection than any shared preset provides (e.g.
+	// always a single best-quality format on Instagram, av1 preference on
+	// YouTube). The override is recorded as a job event. See
+	// service.ParsePlatformPresetOverrides.
+	PlatformPresetOverrides []string `env:"JOB_PLATFORM_PRESET_OVERRIDES"`
+	// DirectoryLayouts lists "domain=template" pairs (domain as
+	// worker.Domain would extract it, e.g.
+	// "youtube.com={uploader}/{year},instagram.com={author}") nesting a
+	// job's output file under a per-platform subdirectory of OutputDir
+	// instead of writing it directly there. The template's {author},
+	// {uploader}/{channel}, {year} and {month} placeholders are resolved
+	// from the downloaded item's own metadata at download time; see
+	// service.ParseDirectoryLayouts and YtDlpRunner.renderDirectoryLayout.
+	DirectoryLayouts []string `env:"JOB_DIRECTORY_LAYOUTS"`
+	// LibraryViewDir, if set, is the root of a parallel directory tree of
+	// hardlinks into OutputDir's files, organized by
+	// platform/<DirectoryLayouts subdirectory, if any>, so a media server
+	// gets a browsable library layout without duplicating storage; see
+	// service.LibraryViewService. Empty disables it.
+	LibraryViewDir string `env:"JOB_LIBRARY_VIEW_DIR"`
+	// LibraryViewSyncInterval is how often the library view tree is
+	// reconciled against current job records; see
+	// service.LibraryViewService.RunSyncLoop.
+	LibraryViewSyncInterval time.Duration `env:"JOB_LIBRARY_VIEW_SYNC_INTERVAL" envDefault:"10m"`
+	// DefaultPresetsByExtractor lists "domain=preset" pairs (e.g.
+	// "tiktok.com=original,soundcloud.com=audio,youtube.com=mp4-1080")
+	// picking the preset used when an enqueue request omits one, keyed by
+	// the source URL's host. A host with no entry falls back to
+	// "original". See service.SetDefaultPresetsByExtractor.
+	DefaultPresetsByExtractor []string `env:"JOB_DEFAULT_PRESETS_BY_EXTRACTOR"`
+	// PlaylistArchiveDir holds one yt-dlp download-archive file per
+	// playlist/channel URL enqueued via JobService.EnqueuePlaylist, so a
+	// duplicate submission of the same source only fetches items not
+	// already downloaded. Persisted to disk so it survives restarts; empty
+	// disables this. See config.Scheduler.ArchiveDir for the analogous
+	// per-schedule archives.
+	PlaylistArchiveDir string `env:"JOB_PLAYLIST_ARCHIVE_DIR" envDefault:"./data/playlists"`
+	// MaxPlaylistItems caps how many items a playlist/channel, album or
+	// reddit gallery expansion enqueues (0 for unlimited), overridable per
+	// request; see JobService.SetMaxPlaylistItems and entity.JobGroup.Truncated.
+	MaxPlaylistItems int `env:"JOB_MAX_PLAYLIST_ITEMS" envDefault:"0"`
+	// MaxQueuedPerIdentity caps how many jobs a single submitter (API key or
+	// source IP, see entity.Job.Submitter) may have waiting in the worker
+	// pool's queue at once (<=0 for unlimited), so one client submitting
+	// hundreds of URLs can't starve everyone else's jobs behind its own. See
+	// worker.Pool.
+	MaxQueuedPerIdentity int `env:"JOB_MAX_QUEUED_PER_IDENTITY" envDefault:"0"`
+	// WorkerStuckTimeout flags a worker whose current job has run longer
+	// than this as stuck, surfaced via worker.Pool.Workers and GET
+	// /v1/admin/workers. <=0 disables the watchdog entirely. Unlike
+	// StallTimeout, this doesn't look at progress output staleness at
+	// all — it catches a job wedged somewhere StallTimeout's own watchdog
+	// can't see, e.g. stuck before the downloader ever starts emitting
+	// progress lines.
+	WorkerStuckTimeout time.Duration `env:"JOB_WORKER_STUCK_TIMEOUT" envDefault:"0"`
+	// WorkerStuckGrace is extra time allowed on top of WorkerStuckTimeout
+	// before WorkerRecycleEnabled takes action, so a worker is flagged as
+	// stuck for a while before anything tries to kill its job.
+	WorkerStuckGrace time.Duration `env:"JOB_WORKER_STUCK_GRACE" envDefault:"5m"`
+	// WorkerRecycleEnabled, with WorkerStuckTimeout set, kills a stuck
+	// job's process once it's been stuck for WorkerStuckTimeout+
+	// WorkerStuckGrace, freeing its worker slot; the job's own retry
+	// policy decides whether it runs again. Off by default: flagging via
+	// GET /v1/admin/workers is always on, killing is opt-in.
+	WorkerRecycleEnabled bool `env:"JOB_WORKER_RECYCLE_ENABLED" envDefault:"false"`
+}
+
+// Retention controls how long job/publication metadata and the files they
+// point to are kept around before being cleaned up. FileTTL is expected to
+// be shorter than or equal to MetadataTTL: files are deleted first and the
+// publication is marked expired, while the metadata record lives on until
+// MetadataTTL so users can still see history and re-enqueue it.
+type Retention struct {
+	FileTTL     time.Duration `env:"RETENTION_FILE_TTL" envDefault:"168h"`
+	MetadataTTL time.Duration `env:"RETENTION_METADATA_TTL" envDefault:"720h"`
+}
+
+// Watch controls the optional watch-folder import of batch URL list files.
+// Dir is empty by default, which disables watching entirely.
+type Watch struct {
+	Dir      string        `env:"WATCH_DIR"`
+	Interval time.Duration `env:"WATCH_INTERVAL" envDefault:"30s"`
+}
+
+// Archive controls the pacing of "archive this whole account" operations.
+type Archive struct {
+	EnqueuePace time.Duration `env:"ARCHIVE_ENQUEUE_PACE" envDefault:"2s"`
+	YtdlpPath   string        `env:"ARCHIVE_YTDLP_PATH"`
+}
+
+// Thumbnail controls generation of the scrub-preview sprite for videos, as
+// well as the single-frame cover thumbnail; see
+// PublicationService.EnsureThumbnail.
+type Thumbnail struct {
+	Interval   time.Duration `env:"THUMBNAIL_INTERVAL" envDefault:"10s"`
+	TileWidth  int           `env:"THUMBNAIL_TILE_WIDTH" envDefault:"160"`
+	TileHeight int           `env:"THUMBNAIL_TILE_HEIGHT" envDefault:"90"`
+	Columns    int           `env:"THUMBNAIL_COLUMNS" envDefault:"10"`
+	// CacheDir holds generated cover thumbnails, one per publication, keyed
+	// by uuid; see GET /v1/publications/{uuid}/thumbnail.
+	CacheDir string `env:"THUMBNAIL_CACHE_DIR" envDefault:"./data/thumbnails"`
+	// FfmpegPath overrides the ffmpeg binary used to generate a cover
+	// thumbnail when yt-dlp didn't already write one; empty resolves
+	// "ffmpeg" from PATH.
+	FfmpegPath string `env:"THUMBNAIL_FFMPEG_PATH"`
+}
+
+// Transcription controls the whisper.cpp transcription queue.
+type Transcription struct {
+	DepDir      string `env:"TRANSCRIPTION_DEP_DIR" envDefault:"./data/deps"`
+	Model       string `env:"TRANSCRIPTION_MODEL" envDefault:"base"`
+	QueueDepth  int    `env:"TRANSCRIPTION_QUEUE_DEPTH" envDefault:"32"`
+	Concurrency int    `env:"TRANSCRIPTION_CONCURRENCY" envDefault:"1"`
+}
+
+// Translation controls the optional subtitle translation step. Exactly one
+// of Endpoint/Command should be set.
+type Translation struct {
+	Endpoint string `env:"TRANSLATION_LIBRETRANSLATE_ENDPOINT"`
+	Command  string `env:"TRANSLATION_COMMAND"`
+}
+
+// Scheduler controls the recurring-download subsystem; see
+// service.SchedulerService.
+type Scheduler struct {
+	// CheckInterval is how often the scheduler polls for schedules due to
+	// fire. It bounds how late a cron-scheduled run can start, not how
+	// precisely it starts on the minute.
+	CheckInterval time.Duration `env:"SCHEDULER_CHECK_INTERVAL" envDefault:"1m"`
+	// ArchiveDir holds each schedule's yt-dlp download-archive file, used
+	// to dedupe recurring runs against items already fetched.
+	ArchiveDir string `env:"SCHEDULER_ARCHIVE_DIR" envDefault:"./data/schedules"`
+}
+
+// Webhook controls the default job-status notification target; jobs can
+// override URL per-request, see entity.Job.Webhook.
+type Webhook struct {
+	URL string `env:"WEBHOOK_URL"`
+	// Secret HMAC-SHA256 signs the payload so receivers can verify it came
+	// from this server; empty disables signing.
+	Secret      string        `env:"WEBHOOK_SECRET"`
+	MaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"3"`
+	BackoffBase time.Duration `env:"WEBHOOK_BACKOFF_BASE" envDefault:"2s"`
+	// AllowPrivateHosts disables the default block on webhook URLs whose
+	// host is a loopback, private, or link-local address. job.Webhook is
+	// fully caller-controlled, so leaving this off by default keeps the
+	// server from being used as an SSRF proxy against internal services;
+	// self-hosted deployments that legitimately want to notify an
+	// internal service can opt back in. See service.ValidateWebhookURL.
+	AllowPrivateHosts bool `env:"WEBHOOK_ALLOW_PRIVATE_HOSTS" envDefault:"false"`
+}
+
+// Support controls the admin support-bundle endpoint.
+type Support struct {
+	// MaxFailedJobs bounds how many recent failed jobs are included in a
+	// generated bundle, newest first.
+	MaxFailedJobs int `env:"SUPPORT_MAX_FAILED_JOBS" envDefault:"20"`
+}
+
+// Logging controls sampling of high-cardinality debug log events (see
+// pkg/logger.SamplingHandler), keeping debug mode usable in production when
+// a single job can otherwise emit thousands of repetitive lines.
+type Logging struct {
+	// SampleRates lists "event=every" pairs (e.g. "progress=50" keeps 1 in
+	// every 50 progress lines); an event not listed uses DefaultSampleEvery.
+	SampleRates []string `env:"LOG_SAMPLE_RATES" envDefault:"progress=50"`
+	// DefaultSampleEvery is the sample rate for events with no entry in
+	// SampleRates; 1 (or less) keeps every record.
+	DefaultSampleEvery int `env:"LOG_DEFAULT_SAMPLE_EVERY" envDefault:"1"`
+}
+
+// RateLimit caps how often a single client may enqueue jobs, keyed by API
+// key (if supplied) or client IP; see middleware.RateLimit.
+type RateLimit struct {
+	// RequestsPerMinute <= 0 disables rate limiting entirely.
+	RequestsPerMinute int `env:"RATE_LIMIT_REQUESTS_PER_MINUTE" envDefault:"0"`
+	// Burst allows this many requests instantly before the steady-state
+	// rate applies.
+	Burst int `env:"RATE_LIMIT_BURST" envDefault:"10"`
+	// MaxConcurrentDownloads caps how many file-serving requests a single
+	// client may have in flight at once, on top of the egress byte-rate
+	// caps; <= 0 disables this limit. See middleware.ConcurrencyLimit.
+	MaxConcurrentDownloads int `env:"RATE_LIMIT_MAX_CONCURRENT_DOWNLOADS" envDefault:"0"`
+}
+
+// Privacy controls redaction of source URLs (which can embed tokens or
+// usernames in their path/query) from logs, job error messages and audit
+// entries; see pkg/privacy.
+type Privacy struct {
+	// RedactURLs, when set, hashes the path/query of source URLs before
+	// they reach logs, job error messages or command audit entries,
+	// keeping the host visible for triage but dropping the rest.
+	RedactURLs bool `env:"PRIVACY_REDACT_URLS" envDefault:"false"`
+	// HashSalt is mixed into the redaction hash so it can't be reversed via
+	// a dictionary of known paths. Should be a random, per-deployment
+	// value when RedactURLs is enabled.
+	HashSalt string `env:"PRIVACY_HASH_SALT"`
+}
+
+// Auth controls the optional multi-key authorization layer; see
+// service.AuthService. KeysFile empty (the default) leaves every route
+// open, same as before this existed: ClientKeyHeader is still read for
+// identity/rate-limiting/defaults purposes, just never checked against a
+// scope.
+type Auth struct {
+	// KeysFile is a JSON array of entity.APIKey, e.g.
+	// `[{"key":"...","scopes":["enqueue","read"],"label":"alice"}]`. Polled
+	// for changes every ReloadInterval so keys can be added, removed or
+	// re-scoped without a restart.
+	KeysFile       string        `env:"AUTH_KEYS_FILE"`
+	ReloadInterval time.Duration `env:"AUTH_RELOAD_INTERVAL" envDefault:"30s"`
+}
+
+// PlatformAPIs holds credentials for optional official platform API calls
+// that enrich a publication's metadata beyond what the extractor's info
+// JSON reports: exact publish date, license, content category. Each
+// platform is independently optional; an empty key/secret leaves that
+// platform's enrichment skipped rather than erroring. See
+// service.PublicationService.EnrichMetadata and internal/enrich.
+type PlatformAPIs struct {
+	// YouTubeAPIKey, if set, enables enrichment for youtube.com/youtu.be
+	// source URLs via the YouTube Data API v3.
+	YouTubeAPIKey string `env:"PLATFORM_YOUTUBE_API_KEY"`
+	// RedditClientID and RedditClientSecret, if both set, enable enrichment
+	// for reddit.com source URLs via Reddit's OAuth script-app API.
+	RedditClientID     string `env:"PLATFORM_REDDIT_CLIENT_ID"`
+	RedditClientSecret string `env:"PLATFORM_REDDIT_CLIENT_SECRET"`
+}
+
+// Redacted returns a copy of c with secrets blanked out, safe to dump into
+// logs, diagnostics or a support bundle.
+func (c Config) Redacted() Config {
+	if c.Webhook.Secret != "" {
+		c.Webhook.Secret = "REDACTED"
+	}
+
+	if c.PlatformAPIs.YouTubeAPIKey != "" {
+		c.PlatformAPIs.YouTubeAPIKey = "REDACTED"
+	}
+
+	if c.PlatformAPIs.RedditClientSecret != "" {
+		c.PlatformAPIs.RedditClientSecret = "REDACTED"
+	}
+
+	return c
+}
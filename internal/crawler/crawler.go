@@ -0,0 +1,126 @@
+// Package crawler defines the plugin interface external extractors
+// (yt-dlp, gallery-dl, ...) implement to let daunrodo probe a source URL
+// without downloading it yet.
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MediaType coarsely categorizes a probed item, driving decisions like
+// preset auto-selection.
+type MediaType string
+
+const (
+	MediaTypeVideo MediaType = "video"
+	MediaTypeAudio MediaType = "audio"
+	MediaTypeImage MediaType = "image"
+)
+
+// Item is a single piece of media discovered while probing a URL, e.g. one
+// video in a channel or one image in a gallery.
+type Item struct {
+	ID       string
+	URL      string
+	Title    string
+	Type     MediaType
+	Duration time.Duration
+}
+
+// Prober lists the items behind an account/channel/playlist URL.
+// maxItems, if positive, caps how many items the prober itself enumerates
+// (e.g. via yt-dlp's --playlist-items) rather than listing everything and
+// discarding the excess; 0 means unlimited.
+type Prober interface {
+	Probe(ctx context.Context, url string, maxItems int) ([]Item, error)
+}
+
+// Format is a single downloadable format reported for a probed URL, as
+// yt-dlp's `--dump-json` output lists it.
+type Format struct {
+	FormatID   string
+	Ext        string
+	Resolution string
+	// FilesizeBytes is 0 when the extractor didn't report a size (exact or
+	// approximate) for this format.
+	FilesizeBytes int64
+}
+
+// Metadata is what MetadataProber.Metadata returns for a single URL:
+// enough to show a user what they're about to download without fetching
+// any of it.
+type Metadata struct {
+	Title    string
+	Duration time.Duration
+	Formats  []Format
+	// EstimatedSizeBytes is the best available size estimate across
+	// Formats (the selected/best format's size, exact or approximate), 0
+	// if none was reported.
+	EstimatedSizeBytes int64
+	// ThumbnailURL is a preview image for the media, reported by
+	// fallback extractors (see internal/crawler/opengraph) that can't
+	// enumerate Formats at all; empty when not available.
+	ThumbnailURL string
+	// DownloadURL, if set, is the URL the native downloader should
+	// actually target instead of the originally probed page URL: a
+	// fallback extractor found it pointing directly at a media file
+	// rather than an HTML page. See FallbackProber.
+	DownloadURL string
+}
+
+// MetadataProber probes a single URL for Metadata without downloading it,
+// e.g. via yt-dlp's `--skip-download --dump-json`. Unlike Prober, it
+// doesn't expand playlists/channels into items; it describes one URL.
+type MetadataProber interface {
+	Metadata(ctx context.Context, url string) (*Metadata, error)
+}
+
+// FallbackProber tries Primary first; if it errors (yt-dlp/gallery-dl
+// couldn't extract the URL), it probes the page via Fallback instead
+// (e.g. opengraph.Prober), for at least a title/thumbnail/direct media
+// URL. If Fallback's result reports a DownloadURL, FallbackProber also
+// retries Primary against that URL, since the native downloader often
+// handles a direct media URL fine even when it couldn't make sense of the
+// page it was embedded in; Primary's retry result wins, backfilled with
+// whatever Fallback found that it didn't.
+type FallbackProber struct {
+	Primary  MetadataProber
+	Fallback MetadataProber
+}
+
+func (p FallbackProber) Metadata(ctx context.Context, url string) (*Metadata, error) {
+	md, err := p.Primary.Metadata(ctx, url)
+	if err == nil {
+		return md, nil
+	}
+
+	primaryErr := err
+
+	fb, err := p.Fallback.Metadata(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("primary prober failed: %w; fallback prober also failed: %v", primaryErr, err)
+	}
+
+	if fb.DownloadURL == "" {
+		return fb, nil
+	}
+
+	retried, err := p.Primary.Metadata(ctx, fb.DownloadURL)
+	if err != nil {
+		return fb, nil
+	}
+
+	if retried.Title == "" {
+		retried.Title = fb.Title
+	}
+
+	if retried.ThumbnailURL == "" {
+		retried.ThumbnailURL = fb.ThumbnailURL
+	}
+
+	retried.DownloadURL = fb.DownloadURL
+
+	return retried, nil
+}
@@ -0,0 +1,127 @@
+// Package opengraph implements crawler.MetadataProber as a last-resort
+// fallback for URLs yt-dlp/gallery-dl can't extract: it fetches the page
+// over HTTP and reads its OpenGraph meta tags and oEmbed discovery link
+// for a title, thumbnail and direct media URL, so daunrodo can still
+// show something useful and, if a direct media URL is found, let the
+// native downloader fetch that instead. See crawler.FallbackProber.
+package opengraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/evan-sm/daunrodo/internal/crawler"
+	"github.com/evan-sm/daunrodo/pkg/safehttp"
+)
+
+// Prober fetches a page's OpenGraph/oEmbed metadata via client.
+type Prober struct {
+	client *safehttp.Client
+}
+
+// NewProber builds a Prober that fetches pages through client.
+func NewProber(client *safehttp.Client) *Prober {
+	return &Prober{client: client}
+}
+
+var (
+	metaTagPattern    = regexp.MustCompile(`(?i)<meta[^>]+(?:property|name)=["']([^"']+)["'][^>]*content=["']([^"']*)["'][^>]*>`)
+	oembedLinkPattern = regexp.MustCompile(`(?i)<link[^>]+type=["']application/json\+oembed["'][^>]+href=["']([^"']*)["'][^>]*>`)
+	titlePattern      = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// Metadata implements crawler.MetadataProber by scraping url's OpenGraph
+// meta tags: og:title, og:video/og:video:url/og:video:secure_url/og:audio
+// (a direct media URL, if the page embeds one) and og:image (a
+// thumbnail), filling in gaps from the page's oEmbed discovery link and
+// finally its plain <title> if OpenGraph reported nothing at all.
+func (p *Prober) Metadata(ctx context.Context, url string) (*crawler.Metadata, error) {
+	body, err := p.client.GetBody(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("opengraph: fetch %s: %w", url, err)
+	}
+
+	html := string(body)
+	tags := parseMetaTags(html)
+
+	md := &crawler.Metadata{
+		Title:        tags["og:title"],
+		ThumbnailURL: tags["og:image"],
+		DownloadURL:  firstNonEmpty(tags["og:video:secure_url"], tags["og:video:url"], tags["og:video"], tags["og:audio"]),
+	}
+
+	if link := oembedLinkPattern.FindStringSubmatch(html); link != nil {
+		if oe, err := p.fetchOEmbed(ctx, link[1]); err == nil {
+			if md.Title == "" {
+				md.Title = oe.Title
+			}
+
+			if md.ThumbnailURL == "" {
+				md.ThumbnailURL = oe.ThumbnailURL
+			}
+		}
+	}
+
+	if md.Title == "" {
+		md.Title = pageTitle(html)
+	}
+
+	if md.Title == "" && md.ThumbnailURL == "" && md.DownloadURL == "" {
+		return nil, fmt.Errorf("opengraph: no usable og/oembed tags found on %s", url)
+	}
+
+	return md, nil
+}
+
+// oembedResponse is the subset of an oEmbed JSON response opengraph uses;
+// see https://oembed.com.
+type oembedResponse struct {
+	Title        string `json:"title"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+func (p *Prober) fetchOEmbed(ctx context.Context, endpoint string) (*oembedResponse, error) {
+	body, err := p.client.GetBody(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var oe oembedResponse
+	if err := json.Unmarshal(body, &oe); err != nil {
+		return nil, err
+	}
+
+	return &oe, nil
+}
+
+func parseMetaTags(html string) map[string]string {
+	tags := make(map[string]string)
+
+	for _, m := range metaTagPattern.FindAllStringSubmatch(html, -1) {
+		tags[strings.ToLower(m[1])] = m[2]
+	}
+
+	return tags
+}
+
+func pageTitle(html string) string {
+	m := titlePattern.FindStringSubmatch(html)
+	if m == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(m[1])
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
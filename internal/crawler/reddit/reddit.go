@@ -0,0 +1,135 @@
+// Package reddit resolves Reddit-specific submission shapes that a plain
+// yt-dlp/gallery-dl run can't handle on its own: a crosspost, which has no
+// media of its own and needs resolving to the post it was crossposted
+// from, and a gallery, which packs several images into one submission and
+// needs expanding into one item per image. See JobService's
+// SetRedditResolver.
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/evan-sm/daunrodo/pkg/safehttp"
+)
+
+// Result is what Resolve found for a submission URL: at most one of
+// Crosspost or GalleryURLs is set; both empty means the URL is an ordinary
+// single-media submission gallery-dl/yt-dlp can already handle.
+type Result struct {
+	// Crosspost is the original submission's URL, if the probed URL was a
+	// crosspost of it.
+	Crosspost string
+	// GalleryURLs lists each image in the submission, in no particular
+	// order (see galleryURLs), if the probed URL was a gallery post.
+	GalleryURLs []string
+}
+
+// Resolver fetches a Reddit submission's public listing JSON (no API
+// credentials required, unlike internal/enrich's authenticated lookup) to
+// detect crossposts and galleries.
+type Resolver struct {
+	client *safehttp.Client
+}
+
+// NewResolver builds a Resolver that fetches submissions through client.
+func NewResolver(client *safehttp.Client) *Resolver {
+	return &Resolver{client: client}
+}
+
+// listing is the subset of Reddit's public "<permalink>.json" response
+// Resolve reads.
+type listing struct {
+	Data struct {
+		Children []struct {
+			Data struct {
+				IsGallery     bool `json:"is_gallery"`
+				MediaMetadata map[string]struct {
+					S struct {
+						U string `json:"u"`
+					} `json:"s"`
+				} `json:"media_metadata"`
+				CrosspostParentList []struct {
+					URL string `json:"url"`
+				} `json:"crosspost_parent_list"`
+			} `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// Resolve fetches url's public listing JSON and reports whether it's a
+// crosspost or a gallery. It returns an error for anything that isn't a
+// Reddit submission URL at all (the caller should fall back to treating
+// url normally rather than failing the job outright).
+func (r *Resolver) Resolve(ctx context.Context, url string) (*Result, error) {
+	endpoint, ok := listingEndpoint(url)
+	if !ok {
+		return nil, fmt.Errorf("reddit: %s is not a submission URL", url)
+	}
+
+	body, err := r.client.GetBody(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("reddit: fetch %s: %w", endpoint, err)
+	}
+
+	var listings []listing
+	if err := json.Unmarshal(body, &listings); err != nil {
+		return nil, fmt.Errorf("reddit: parse %s: %w", endpoint, err)
+	}
+
+	if len(listings) == 0 || len(listings[0].Data.Children) == 0 {
+		return nil, fmt.Errorf("reddit: no submission found at %s", url)
+	}
+
+	post := listings[0].Data.Children[0].Data
+
+	if len(post.CrosspostParentList) > 0 {
+		return &Result{Crosspost: post.CrosspostParentList[0].URL}, nil
+	}
+
+	if post.IsGallery && len(post.MediaMetadata) > 0 {
+		return &Result{GalleryURLs: galleryURLs(post.MediaMetadata)}, nil
+	}
+
+	return &Result{}, nil
+}
+
+// galleryURLs collects metadata's image URLs, unescaping the HTML entities
+// Reddit encodes them with (e.g. "&amp;" in the query string). Map
+// iteration order is random, so the caller sees galleries in an
+// unspecified order that can differ from one call to the next.
+func galleryURLs(metadata map[string]struct {
+	S struct {
+		U string `json:"u"`
+	} `json:"s"`
+}) []string {
+	urls := make([]string, 0, len(metadata))
+
+	for _, item := range metadata {
+		if item.S.U == "" {
+			continue
+		}
+
+		urls = append(urls, html.UnescapeString(item.S.U))
+	}
+
+	return urls
+}
+
+// listingEndpoint derives a submission URL's public listing JSON endpoint
+// (its permalink with ".json" appended), and reports whether url looks
+// like a Reddit submission URL at all.
+func listingEndpoint(url string) (string, bool) {
+	if !strings.Contains(url, "reddit.com/") {
+		return "", false
+	}
+
+	if !strings.Contains(url, "/comments/") {
+		return "", false
+	}
+
+	return strings.TrimSuffix(url, "/") + ".json", true
+}
@@ -0,0 +1,24 @@
+package ytdlp
+
+import (
+	"fmt"
+	"time"
+)
+
+// DownloadSectionsArg builds yt-dlp's --download-sections value for a clip
+// range. Either bound may be nil for an open range. When yt-dlp's own
+// keyframe-based trimming isn't precise enough, callers fall back to
+// re-encoding the clip with ffmpeg using the same bounds.
+func DownloadSectionsArg(start, end *time.Duration) string {
+	startStr := "0"
+	if start != nil {
+		startStr = fmt.Sprintf("%.3f", start.Seconds())
+	}
+
+	endStr := "inf"
+	if end != nil {
+		endStr = fmt.Sprintf("%.3f", end.Seconds())
+	}
+
+	return fmt.Sprintf("*%s-%s", startStr, endStr)
+}
@@ -0,0 +1,156 @@
+package ytdlp
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/crawler"
+)
+
+// TestParseFlatPlaylist runs parseFlatPlaylist against real `yt-dlp
+// --flat-playlist -J` output captured per platform, so a future yt-dlp
+// upgrade that changes a platform's field shapes fails a unit test instead
+// of a production job.
+func TestParseFlatPlaylist(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		wantLen  int
+		wantItem crawler.Item
+	}{
+		{
+			name:    "youtube",
+			fixture: "testdata/youtube_flat_playlist.json",
+			wantLen: 2,
+			wantItem: crawler.Item{
+				ID:       "dQw4w9WgXcQ",
+				URL:      "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+				Title:    "Rick Astley - Never Gonna Give You Up",
+				Type:     crawler.MediaTypeVideo,
+				Duration: 212 * time.Second,
+			},
+		},
+		{
+			name:    "tiktok",
+			fixture: "testdata/tiktok_flat_playlist.json",
+			wantLen: 1,
+			wantItem: crawler.Item{
+				ID:       "7123456789012345678",
+				URL:      "https://www.tiktok.com/@someuser/video/7123456789012345678",
+				Title:    "someuser on TikTok",
+				Type:     crawler.MediaTypeVideo,
+				Duration: 15 * time.Second,
+			},
+		},
+		{
+			name:    "instagram",
+			fixture: "testdata/instagram_flat_playlist.json",
+			wantLen: 1,
+			wantItem: crawler.Item{
+				ID:       "Cabc123XYZ",
+				URL:      "https://www.instagram.com/p/Cabc123XYZ/",
+				Title:    "",
+				Type:     crawler.MediaTypeVideo,
+				Duration: 34 * time.Second,
+			},
+		},
+		{
+			name:    "twitter",
+			fixture: "testdata/twitter_flat_playlist.json",
+			wantLen: 1,
+			wantItem: crawler.Item{
+				ID:       "1234567890123456789",
+				URL:      "https://twitter.com/someuser/status/1234567890123456789",
+				Title:    "someuser on X",
+				Type:     crawler.MediaTypeVideo,
+				Duration: 0,
+			},
+		},
+		{
+			name:    "reddit",
+			fixture: "testdata/reddit_flat_playlist.json",
+			wantLen: 1,
+			wantItem: crawler.Item{
+				ID:       "abc123",
+				URL:      "https://v.redd.it/abc123",
+				Title:    "A very cool clip",
+				Type:     crawler.MediaTypeVideo,
+				Duration: 8400 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(tt.fixture)
+			if err != nil {
+				t.Fatalf("read fixture: %v", err)
+			}
+
+			items, err := parseFlatPlaylist(data)
+			if err != nil {
+				t.Fatalf("parseFlatPlaylist: %v", err)
+			}
+
+			if len(items) != tt.wantLen {
+				t.Fatalf("got %d items, want %d", len(items), tt.wantLen)
+			}
+
+			if items[0] != tt.wantItem {
+				t.Errorf("items[0] = %+v, want %+v", items[0], tt.wantItem)
+			}
+		})
+	}
+}
+
+func TestParseFlatPlaylistInvalidJSON(t *testing.T) {
+	if _, err := parseFlatPlaylist([]byte("not json")); err == nil {
+		t.Fatal("expected an error for invalid JSON, got nil")
+	}
+}
+
+// TestParseDumpJSON runs parseDumpJSON against captured `yt-dlp
+// --skip-download --dump-json` output, checking it picks a sensible
+// filesize out of both exact and approximate fields.
+func TestParseDumpJSON(t *testing.T) {
+	data, err := os.ReadFile("testdata/youtube_dump_json.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	meta, err := parseDumpJSON(data)
+	if err != nil {
+		t.Fatalf("parseDumpJSON: %v", err)
+	}
+
+	want := crawler.Metadata{
+		Title:    "Rick Astley - Never Gonna Give You Up",
+		Duration: 212 * time.Second,
+		Formats: []crawler.Format{
+			{FormatID: "18", Ext: "mp4", Resolution: "640x360", FilesizeBytes: 10485760},
+			{FormatID: "22", Ext: "mp4", Resolution: "1280x720", FilesizeBytes: 15728640},
+		},
+		EstimatedSizeBytes: 15728640,
+	}
+
+	if meta.Title != want.Title || meta.Duration != want.Duration || meta.EstimatedSizeBytes != want.EstimatedSizeBytes {
+		t.Errorf("got %+v, want %+v", meta, want)
+	}
+
+	if len(meta.Formats) != len(want.Formats) {
+		t.Fatalf("got %d formats, want %d", len(meta.Formats), len(want.Formats))
+	}
+
+	for i, f := range meta.Formats {
+		if f != want.Formats[i] {
+			t.Errorf("formats[%d] = %+v, want %+v", i, f, want.Formats[i])
+		}
+	}
+}
+
+func TestParseDumpJSONInvalidJSON(t *testing.T) {
+	if _, err := parseDumpJSON([]byte("not json")); err == nil {
+		t.Fatal("expected an error for invalid JSON, got nil")
+	}
+}
@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"net/http"
+
+	"github.com/evan-sm/daunrodo/internal/service"
+	"github.com/evan-sm/daunrodo/internal/worker"
+)
+
+// getSupportBundle handles GET /v1/admin/support-bundle: a zip of redacted
+// config, version info, dependency status, worker pool load and recent
+// failed jobs' command lines and errors, for attaching to bug reports.
+func (h *Handler) getSupportBundle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="daunrodo-support-bundle.zip"`)
+
+	zw := zip.NewWriter(w)
+	defer zw.Close()
+
+	if err := h.support.Generate(r.Context(), zw); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getQueue handles GET /v1/admin/queue: queue depth, per-worker current
+// job, oldest queued job age, worker utilization and aggregate download
+// throughput, for operators diagnosing a backlog or a saturated uplink.
+func (h *Handler) getQueue(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		worker.Stats
+		ThroughputBytesPerSec float64 `json:"throughput_bytes_per_sec"`
+	}{
+		Stats:                 h.jobs.PoolStats(),
+		ThroughputBytesPerSec: h.jobs.ThroughputBytesPerSec(),
+	})
+}
+
+// getWorkers handles GET /v1/admin/workers: a snapshot of every worker
+// goroutine, its current job (if any) and how long it's been in that
+// state, flagging any that have held a job past config.Job.WorkerStuckTimeout;
+// see worker.Pool.Workers. Diagnoses the "queue stops draining" class of
+// incident without having to correlate job logs by hand.
+func (h *Handler) getWorkers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.jobs.PoolWorkers())
+}
+
+// getPublicationStats handles GET /v1/admin/publications/stats: aggregate
+// download counts across every publication, for tracking how much of the
+// library actually gets re-fetched versus sitting unused.
+func (h *Handler) getPublicationStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.pubs.Stats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(stats)
+}
+
+// drainQueue handles POST /v1/admin/queue/drain: stops the worker pool from
+// accepting new jobs, letting already-running ones finish. There is no
+// undrain; restart the server to resume accepting jobs.
+func (h *Handler) drainQueue(w http.ResponseWriter, r *http.Request) {
+	h.jobs.DrainPool()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.jobs.PoolStats())
+}
+
+type purgeRequest struct {
+	// URLPattern matches against job source URLs, substring and
+	// case-insensitive.
+	URLPattern string `json:"url_pattern"`
+	// Uploader matches against a publication's recorded author/uploader
+	// name, substring and case-insensitive.
+	Uploader string `json:"uploader"`
+}
+
+// purgeData handles POST /v1/admin/purge: deletes every job and
+// publication (and its file) matching req.URLPattern or req.Uploader,
+// returning a report of what was removed. For operators handling takedown
+// or GDPR erasure requests.
+func (h *Handler) purgeData(w http.ResponseWriter, r *http.Request) {
+	var req purgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.URLPattern == "" && req.Uploader == "" {
+		http.Error(w, "url_pattern or uploader is required", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.purge.Purge(r.Context(), service.PurgeRequest{URLPattern: req.URLPattern, Uploader: req.Uploader})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type archiveRequest struct {
+	URL    string `json:"url"`
+	Preset string `json:"preset"`
+}
+
+// archiveAccount handles POST /v1/archive: probes an account/channel URL
+// and enqueues one job per discovered item.
+func (h *Handler) archiveAccount(w http.ResponseWriter, r *http.Request) {
+	var req archiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.archive.Archive(r.Context(), req.URL, req.Preset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(group)
+}
+
+// resumeArchive handles POST /v1/archive/{id}/resume: re-probes the source
+// and enqueues only items missing from the group.
+func (h *Handler) resumeArchive(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.archive.Resume(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(group)
+}
+
+// syncGroup handles POST /v1/groups/{id}/sync: a differential re-probe that
+// enqueues only new items and reports items removed from the source.
+func (h *Handler) syncGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	_, result, err := h.archive.Sync(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evan-sm/daunrodo/internal/middleware"
+)
+
+// exampleRoute is one row of the GET /v1/examples response: an apiRoute
+// plus the request snippets generated for it.
+type exampleRoute struct {
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+	Summary string `json:"summary"`
+	Curl    string `json:"curl"`
+	Python  string `json:"python"`
+	Go      string `json:"go"`
+}
+
+// baseURL reconstructs the absolute URL this instance is reachable at from
+// r, so the generated snippets are copy-pasteable as-is rather than
+// relative paths the caller has to stitch together themselves. Trusts
+// X-Forwarded-Proto ahead of r.TLS since daunrodo is commonly run behind a
+// TLS-terminating proxy; see middleware.RealIP for the same assumption
+// applied to client IPs.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	if fwd := r.Header.Get("X-Forwarded-Proto"); fwd != "" {
+		scheme = fwd
+	}
+
+	return scheme + "://" + r.Host
+}
+
+// examples builds the request snippets for every route in h.routes,
+// against origin (see baseURL). Each scoped route's snippets include the
+// client key header when h.auth has keys loaded. Routes registered via
+// routeOpen get no header, including /me/defaults, whose handlers read the
+// key themselves.
+func (h *Handler) examples(origin string) []exampleRoute {
+	authed := h.auth != nil && h.auth.Configured()
+
+	rows := make([]exampleRoute, 0, len(h.routes))
+
+	for _, rt := range h.routes {
+		url := origin + rt.Pattern
+		needsKey := authed && rt.Scope != ""
+
+		rows = append(rows, exampleRoute{
+			Method:  rt.Method,
+			Pattern: rt.Pattern,
+			Summary: rt.Summary,
+			Curl:    curlExample(rt.Method, url, needsKey),
+			Python:  pythonExample(rt.Method, url, needsKey),
+			Go:      goExample(rt.Method, url, needsKey),
+		})
+	}
+
+	return rows
+}
+
+func curlExample(method, url string, needsKey bool) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "curl -X %s", method)
+
+	if needsKey {
+		fmt.Fprintf(&b, " -H '%s: YOUR_API_KEY'", middleware.ClientKeyHeader)
+	}
+
+	fmt.Fprintf(&b, " '%s'", url)
+
+	return b.String()
+}
+
+func pythonExample(method, url string, needsKey bool) string {
+	var b strings.Builder
+
+	b.WriteString("import requests\n\n")
+
+	headers := "{}"
+	if needsKey {
+		headers = fmt.Sprintf("{%q: %q}", middleware.ClientKeyHeader, "YOUR_API_KEY")
+	}
+
+	fmt.Fprintf(&b, "requests.request(%q, %q, headers=%s)\n", method, url, headers)
+
+	return b.String()
+}
+
+func goExample(method, url string, needsKey bool) string {
+	var b strings.Builder
+
+	b.WriteString("req, _ := http.NewRequest(" + quote(method) + ", " + quote(url) + ", nil)\n")
+
+	if needsKey {
+		fmt.Fprintf(&b, "req.Header.Set(%s, %q)\n", quote(middleware.ClientKeyHeader), "YOUR_API_KEY")
+	}
+
+	b.WriteString("resp, _ := http.DefaultClient.Do(req)\n")
+
+	return b.String()
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
+
+// getExamples handles GET /v1/examples: curl, Python and Go request
+// snippets for every route in h.routes, generated from the instance's own
+// actual base URL and auth mode rather than hand-maintained documentation
+// that can drift from what's mounted; see openapi.go's getOpenAPI for the
+// same h.routes-as-source-of-truth approach.
+func (h *Handler) getExamples(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.examples(baseURL(r)))
+}
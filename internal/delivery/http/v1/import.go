@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const maxImportBody = 10 << 20 // 10MB
+
+// importJobs handles POST /v1/jobs/import: a multipart upload with a
+// "file" field containing a batch of "url[,preset]" lines.
+func (h *Handler) importJobs(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBody)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing \"file\" field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	preset := r.FormValue("preset")
+
+	group, results, err := h.imports.Import(r.Context(), header.Filename, preset, file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(struct {
+		Group   any `json:"group"`
+		Results any `json:"results"`
+	}{Group: group, Results: results})
+}
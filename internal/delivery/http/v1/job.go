@@ -0,0 +1,586 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/middleware"
+	"github.com/evan-sm/daunrodo/internal/preset"
+	"github.com/evan-sm/daunrodo/internal/service"
+)
+
+type enqueueRequest struct {
+	URL    string `json:"url"`
+	Preset string `json:"preset"`
+	// Start/End clip the download to a time range, parsed as Go durations
+	// (e.g. "30s", "1m30s") and mapped to yt-dlp's --download-sections.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Profile names a cookie/account profile; jobs sharing one are
+	// serialized by the worker pool to avoid tripping anti-abuse systems.
+	Profile string `json:"profile"`
+	// Gif requests an additional animated-image artifact ("gif", "webp" or
+	// "avif") be produced alongside the original download.
+	Gif *gifRequest `json:"gif"`
+	// Image requests post-processing of gallery-dl image results.
+	Image *imageRequest `json:"image"`
+	// Comments opts into collecting the source's comments, bounded by
+	// MaxCount (0 means use the server default).
+	Comments *commentsRequest `json:"comments"`
+	// Transcribe opts into post-download whisper.cpp transcription.
+	Transcribe *transcribeRequest `json:"transcribe"`
+	// TranslateTo requests translated subtitle artifacts for these
+	// BCP-47 language tags.
+	TranslateTo []string `json:"translate_to"`
+	// Webhook overrides the server-wide default webhook URL for this job's
+	// status notifications.
+	Webhook string `json:"webhook"`
+	// DryRun performs validation, preset/downloader resolution and command
+	// construction, returning the would-be command line without enqueueing
+	// or running anything.
+	DryRun bool `json:"dry_run"`
+	// Playlist treats url as a playlist/channel: it is probed and expanded
+	// into one child job per item under an entity.JobGroup, instead of
+	// being downloaded as a single opaque job.
+	Playlist bool `json:"playlist"`
+	// Labels are free-form key/value tags, e.g. to group downloads by
+	// project or client; see GET /v1/jobs?label=key:value.
+	Labels map[string]string `json:"labels"`
+	// Dedupe controls how a repeat request for the same URL+preset is
+	// handled: "reuse" returns the existing job, "force_new" (the default)
+	// always creates a new one, and "reuse_if_fresh:<duration>" (e.g.
+	// "reuse_if_fresh:1h") reuses it only if still within that age; see
+	// service.EnqueueOptions.Dedupe.
+	Dedupe string `json:"dedupe"`
+	// MaxRateKbps caps this job's own download bandwidth in kilobits/sec,
+	// overriding the server-wide cap when lower than it; 0 defers to it
+	// entirely.
+	MaxRateKbps int `json:"max_rate_kbps"`
+	// MaxItems caps how many items a Playlist expansion (or an album/reddit
+	// gallery) enqueues, overriding the server-wide default when positive;
+	// 0 defers to it entirely. See service.JobService.SetMaxPlaylistItems.
+	MaxItems int `json:"max_items"`
+}
+
+// faultModeHeader requests a simulated downloader failure mode for this job,
+// honored only when the server has fault injection enabled. It's a header
+// rather than a body field since it's test-only plumbing, not part of the
+// public request contract.
+const faultModeHeader = "X-Daunrodo-Fault-Mode"
+
+type transcribeRequest struct {
+	Model string `json:"model"`
+}
+
+type commentsRequest struct {
+	MaxCount int `json:"max_count"`
+}
+
+type gifRequest struct {
+	Format   string `json:"format"`
+	MaxWidth int    `json:"max_width"`
+	FPS      int    `json:"fps"`
+}
+
+type imageRequest struct {
+	MaxDimension int    `json:"max_dimension"`
+	Format       string `json:"format"`
+	Quality      int    `json:"quality"`
+	StripEXIF    bool   `json:"strip_exif"`
+}
+
+// parseEnqueueRequest decodes an enqueue request body in whichever format
+// the client sent it in: a GET request is parsed from its query string
+// (only reachable when config.Server.EnqueueViaQueryEnabled routes it
+// here), a POST with an application/x-www-form-urlencoded body is parsed
+// as form values, and everything else falls back to the JSON body. Only
+// flat scalar fields are supported via query/form; structured fields such
+// as gif, image, comments, transcribe and translate_to remain JSON-only.
+func parseEnqueueRequest(r *http.Request) (enqueueRequest, error) {
+	if r.Method == http.MethodGet {
+		if err := r.ParseForm(); err != nil {
+			return enqueueRequest{}, fmt.Errorf("parse query: %w", err)
+		}
+
+		return enqueueRequestFromValues(r.Form), nil
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return enqueueRequest{}, fmt.Errorf("parse form: %w", err)
+		}
+
+		return enqueueRequestFromValues(r.PostForm), nil
+	}
+
+	var req enqueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return enqueueRequest{}, fmt.Errorf("decode body: %w", err)
+	}
+
+	return req, nil
+}
+
+// enqueueRequestFromValues populates an enqueueRequest's flat scalar fields
+// from form or query values; see parseEnqueueRequest.
+func enqueueRequestFromValues(v url.Values) enqueueRequest {
+	playlist, _ := strconv.ParseBool(v.Get("playlist"))
+	dryRun, _ := strconv.ParseBool(v.Get("dry_run"))
+	maxRateKbps, _ := strconv.Atoi(v.Get("max_rate_kbps"))
+	maxItems, _ := strconv.Atoi(v.Get("max_items"))
+
+	return enqueueRequest{
+		URL:         v.Get("url"),
+		Preset:      v.Get("preset"),
+		Start:       v.Get("start"),
+		End:         v.Get("end"),
+		Profile:     v.Get("profile"),
+		Webhook:     v.Get("webhook"),
+		Dedupe:      v.Get("dedupe"),
+		Playlist:    playlist,
+		DryRun:      dryRun,
+		MaxRateKbps: maxRateKbps,
+		MaxItems:    maxItems,
+	}
+}
+
+func (h *Handler) enqueueJob(w http.ResponseWriter, r *http.Request) {
+	req, err := parseEnqueueRequest(r)
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if verrs := validateEnqueue(req, h.allowPrivateWebhookHosts); len(verrs.Errors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(verrs)
+
+		return
+	}
+
+	var warnings []string
+	if dep, ok := preset.DeprecationFor(req.Preset); ok {
+		warnings = append(warnings, dep.Message)
+
+		if dep.Sunset != "" {
+			w.Header().Set("Sunset", dep.Sunset)
+		}
+	}
+
+	opts := service.EnqueueOptions{
+		URL:         req.URL,
+		Preset:      req.Preset,
+		Profile:     req.Profile,
+		Webhook:     req.Webhook,
+		FaultMode:   r.Header.Get(faultModeHeader),
+		RequestID:   middleware.RequestIDFromContext(r.Context()),
+		Dedupe:      service.DedupePolicy(req.Dedupe),
+		Labels:      req.Labels,
+		MaxRateKbps: req.MaxRateKbps,
+		MaxItems:    req.MaxItems,
+		APIKey:      r.Header.Get(middleware.ClientKeyHeader),
+		Submitter:   middleware.ClientIdentity(r),
+	}
+
+	// Already validated by validateEnqueue, so these can't fail here.
+	opts.ClipStart, _ = parseClipBound(req.Start)
+	opts.ClipEnd, _ = parseClipBound(req.End)
+
+	if req.Gif != nil {
+		opts.GifOutput = &entity.GifOptions{
+			Format:   entity.ArtifactType(req.Gif.Format),
+			MaxWidth: req.Gif.MaxWidth,
+			FPS:      req.Gif.FPS,
+		}
+	}
+
+	if req.Image != nil {
+		opts.ImageTransform = &entity.ImageTransform{
+			MaxDimension: req.Image.MaxDimension,
+			Format:       req.Image.Format,
+			Quality:      req.Image.Quality,
+			StripEXIF:    req.Image.StripEXIF,
+		}
+	}
+
+	if req.Comments != nil {
+		opts.Comments = &entity.CommentsOptions{MaxCount: req.Comments.MaxCount}
+	}
+
+	if req.Transcribe != nil {
+		opts.Transcription = &entity.TranscriptionOptions{Model: req.Transcribe.Model}
+	}
+
+	if len(req.TranslateTo) > 0 {
+		opts.Translation = &entity.TranslationOptions{TargetLangs: req.TranslateTo}
+	}
+
+	if req.DryRun {
+		result, err := h.jobs.DryRun(r.Context(), opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+
+		return
+	}
+
+	if req.Playlist {
+		group, err := h.jobs.EnqueuePlaylist(r.Context(), req.URL, req.Preset, "", req.MaxItems)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(group)
+
+		return
+	}
+
+	job, err := h.jobs.EnqueueWithOptions(r.Context(), opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := enqueueResponse{Job: job, Warnings: warnings}
+	resp.QueuePosition, resp.EstimatedWait, resp.WaitConfidence = h.jobs.EstimateWait(job.URL)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// enqueueResponse embeds the created job alongside an estimate of how long
+// it'll wait in the queue before a worker picks it up, computed from the
+// current backlog and an EWMA of historical job durations for its source
+// domain; see JobService.EstimateWait. WaitConfidence grades how much to
+// trust EstimatedWait, since a domain with little history or a queue stuck
+// well past its usual duration can't support a precise number.
+// QueuePosition is 1-based and omitted (along with EstimatedWait and
+// WaitConfidence) when no estimate could be made.
+type enqueueResponse struct {
+	*entity.Job
+	QueuePosition  int                    `json:"queue_position,omitempty"`
+	EstimatedWait  time.Duration          `json:"estimated_wait,omitempty"`
+	WaitConfidence service.WaitConfidence `json:"wait_confidence,omitempty"`
+	// Warnings flags non-fatal problems with the request, e.g. a
+	// deprecated preset name kept working for backward compatibility; see
+	// preset.Deprecations. Omitted when there's nothing to warn about.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// parseClipBound parses an optional clip boundary, returning nil for an
+// empty string.
+func parseClipBound(s string) (*time.Duration, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// deleteJob handles DELETE /v1/jobs/{id}: purges a single finished job
+// (along with its publication and file) when called with ?purge=true;
+// without that query parameter it's a 400, since deleting a job record
+// without its file would just leave the file orphaned, and there's no
+// soft-delete state for a caller to undo into later.
+func (h *Handler) deleteJob(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("purge") != "true" {
+		http.Error(w, "set ?purge=true to confirm deletion", http.StatusBadRequest)
+		return
+	}
+
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.purge.PurgeJob(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrJobNotFinished) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+type jobPurgeRequest struct {
+	Status    string `json:"status"`
+	OlderThan string `json:"older_than"`
+}
+
+// purgeJobs handles POST /v1/jobs/purge: bulk-deletes finished jobs
+// matching req's filters, along with their publications and files.
+func (h *Handler) purgeJobs(w http.ResponseWriter, r *http.Request) {
+	var req jobPurgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	filter := service.JobPurgeFilter{Status: entity.JobStatus(req.Status)}
+
+	if req.OlderThan != "" {
+		d, err := time.ParseDuration(req.OlderThan)
+		if err != nil {
+			http.Error(w, "invalid older_than duration", http.StatusBadRequest)
+			return
+		}
+
+		filter.OlderThan = d
+	}
+
+	report, err := h.purge.PurgeJobs(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+type probeRequest struct {
+	URL string `json:"url"`
+}
+
+type probeResponse struct {
+	Title              string        `json:"title"`
+	DurationSeconds    float64       `json:"duration_seconds"`
+	Formats            []probeFormat `json:"formats"`
+	EstimatedSizeBytes int64         `json:"estimated_size_bytes"`
+}
+
+type probeFormat struct {
+	FormatID      string `json:"format_id"`
+	Ext           string `json:"ext"`
+	Resolution    string `json:"resolution"`
+	FilesizeBytes int64  `json:"filesize_bytes"`
+}
+
+// probeJob handles POST /v1/jobs/probe: runs yt-dlp against req.URL with
+// --skip-download --dump-json and returns its title, duration, available
+// formats and estimated size, without creating a job or downloading
+// anything.
+func (h *Handler) probeJob(w http.ResponseWriter, r *http.Request) {
+	var req probeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.URL == "" {
+		http.Error(w, "url is required", http.StatusBadRequest)
+		return
+	}
+
+	meta, err := h.jobs.ProbeMetadata(r.Context(), req.URL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	formats := make([]probeFormat, 0, len(meta.Formats))
+	for _, f := range meta.Formats {
+		formats = append(formats, probeFormat{
+			FormatID:      f.FormatID,
+			Ext:           f.Ext,
+			Resolution:    f.Resolution,
+			FilesizeBytes: f.FilesizeBytes,
+		})
+	}
+
+	resp := probeResponse{
+		Title:              meta.Title,
+		DurationSeconds:    meta.Duration.Seconds(),
+		Formats:            formats,
+		EstimatedSizeBytes: meta.EstimatedSizeBytes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// cancelJob handles POST /v1/jobs/{id}/cancel: kills the job's downloader
+// process (if running) and marks it JobStatusCancelled.
+func (h *Handler) cancelJob(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Cancel(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+// pauseJob handles POST /v1/jobs/{id}/pause: suspends the running
+// downloader process, leaving partial output in place.
+func (h *Handler) pauseJob(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Pause(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+// resumeJob handles POST /v1/jobs/{id}/resume: resumes a previously paused
+// downloader process.
+func (h *Handler) resumeJob(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Resume(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+// listJobs handles GET /v1/jobs: lists all jobs, newest first, optionally
+// filtered to those tagged with ?label=key:value.
+func (h *Handler) listJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.jobs.List(r.Context(), r.URL.Query().Get("label"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(jobs)
+}
+
+func (h *Handler) getJob(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+// getJobLogs handles GET /v1/jobs/{id}/logs: returns the downloader's
+// captured stdout/stderr (size-capped and secret-redacted) across every
+// retry attempt, so users can self-diagnose a failed job without operator
+// access to server logs.
+func (h *Handler) getJobLogs(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(job.LogSnapshot()))
+}
+
+// getJobRuns handles GET /v1/jobs/{id}/runs: returns one entry per
+// downloader execution attempt (start/end time, exit code, stderr tail,
+// proxy used, bytes downloaded), for debugging why a specific retry failed
+// without grepping the combined job log.
+func (h *Handler) getJobRuns(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(job.RunsSnapshot())
+}
+
+// getJobCommands handles GET /v1/jobs/{id}/commands: returns the
+// reproducible (secrets-redacted) command lines recorded for job's external
+// tool invocations, so a failure can be reproduced manually.
+func (h *Handler) getJobCommands(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var commands []string
+
+	for _, ev := range job.EventsSnapshot() {
+		if ev.Type == "command" {
+			commands = append(commands, ev.Message)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(commands)
+}
@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/middleware"
+	"github.com/evan-sm/daunrodo/internal/service"
+)
+
+type keyDefaultsRequest struct {
+	Preset  string            `json:"preset"`
+	Profile string            `json:"profile"`
+	Webhook string            `json:"webhook"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// getMyDefaults handles GET /v1/me/defaults: returns the calling API key's
+// server-stored default enqueue options, so a minimal client can enqueue
+// with just a URL and inherit them; see EnqueueOptions.APIKey.
+func (h *Handler) getMyDefaults(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(middleware.ClientKeyHeader)
+	if key == "" {
+		http.Error(w, "missing "+middleware.ClientKeyHeader+" header", http.StatusUnauthorized)
+		return
+	}
+
+	defaults, err := h.keyDefaults.Get(r.Context(), key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(defaults)
+}
+
+// putMyDefaults handles PUT /v1/me/defaults: stores the calling API key's
+// default enqueue options, replacing whatever it had set before.
+func (h *Handler) putMyDefaults(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(middleware.ClientKeyHeader)
+	if key == "" {
+		http.Error(w, "missing "+middleware.ClientKeyHeader+" header", http.StatusUnauthorized)
+		return
+	}
+
+	var req keyDefaultsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Webhook != "" {
+		if err := service.ValidateWebhookURL(req.Webhook, h.allowPrivateWebhookHosts); err != nil {
+			http.Error(w, "webhook: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	defaults := &entity.KeyDefaults{
+		Key:     key,
+		Preset:  req.Preset,
+		Profile: req.Profile,
+		Webhook: req.Webhook,
+		Labels:  req.Labels,
+	}
+
+	if err := h.keyDefaults.Set(r.Context(), defaults); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(defaults)
+}
@@ -0,0 +1,419 @@
+package v1
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/service"
+	"github.com/google/uuid"
+)
+
+// listPublications handles GET /v1/publications: every publication
+// matching the given filters, newest first, for building a media library
+// view without having to already know which job produced each one. All
+// query parameters are optional and combine with AND.
+func (h *Handler) listPublications(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter := service.PublicationFilter{
+		Platform:      q.Get("platform"),
+		Author:        q.Get("author"),
+		Type:          q.Get("type"),
+		TitleContains: q.Get("title"),
+	}
+
+	var err error
+
+	if v := q.Get("created_after"); v != "" {
+		if filter.CreatedAfter, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, "invalid created_after, expected RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if v := q.Get("created_before"); v != "" {
+		if filter.CreatedBefore, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, "invalid created_before, expected RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	pubs, err := h.pubs.List(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pubs)
+}
+
+// getJobPublications handles GET /v1/jobs/{id}/publications: every
+// publication job id produced, see PublicationService.ListByJob.
+func (h *Handler) getJobPublications(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	pubs, err := h.pubs.ListByJob(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pubs)
+}
+
+func (h *Handler) getPublication(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("uuid"))
+	if err != nil {
+		http.Error(w, "invalid publication uuid", http.StatusBadRequest)
+		return
+	}
+
+	pub, err := h.pubs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pub)
+}
+
+// getJobPublication handles GET /v1/jobs/{id}/publication: the publication
+// produced by a finished job, so a client polling GET /v1/jobs/{id} has a
+// way to find the publication uuid /v1/files/{id} and /v1/publications/{uuid}
+// expect, without already having to know it. 404 if the job hasn't produced
+// one (not done yet, or it errored/was cancelled).
+func (h *Handler) getJobPublication(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	pub, err := h.pubs.GetByJob(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pub)
+}
+
+// refetchPublication creates a new job from an expired publication's stored
+// source URL and preset so the file can be re-downloaded.
+func (h *Handler) refetchPublication(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("uuid"))
+	if err != nil {
+		http.Error(w, "invalid publication uuid", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.pubs.Refetch(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotExpired) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+// getPublicationFile handles GET /v1/publications/{uuid}/file: redirects to
+// the content-addressed /v1/files/{sha256} route, which is what should
+// actually be linked/cached/fronted by a CDN since finished artifacts never
+// change.
+func (h *Handler) getPublicationFile(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("uuid"))
+	if err != nil {
+		http.Error(w, "invalid publication uuid", http.StatusBadRequest)
+		return
+	}
+
+	pub, err := h.pubs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if pub.FilePath == "" {
+		http.Error(w, "file no longer available", http.StatusGone)
+		return
+	}
+
+	sha256, err := h.pubs.EnsureFileHash(r.Context(), pub)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/v1/files/"+sha256, http.StatusFound)
+}
+
+// getFileByID handles GET /v1/files/{id}: serves a finished artifact's file,
+// looked up either by its content hash (the original, cacheable-forever
+// form) or, as a convenience for clients that already hold one, by the
+// owning publication's uuid.
+func (h *Handler) getFileByID(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if pubID, err := parseUUID(id); err == nil {
+		h.getFileByPublicationID(w, r, pubID)
+		return
+	}
+
+	h.getFileBySHA256(w, r, id)
+}
+
+// getFileBySHA256 serves a finished artifact's file under its content hash,
+// which never changes, so the response is safe to cache forever and front
+// with a CDN. Range requests (seeking/resuming a large video) are handled
+// by FileServer.Serve's underlying http.ServeContent, which also sniffs
+// Content-Type from the file itself if setFilePreviewHeaders's
+// extension-based guess came up empty.
+func (h *Handler) getFileBySHA256(w http.ResponseWriter, r *http.Request, sha256 string) {
+	pub, err := h.pubs.GetByFileSHA256(r.Context(), sha256)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if pub.FilePath == "" {
+		http.Error(w, "file no longer available", http.StatusGone)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	setFilePreviewHeaders(w, pub, sha256)
+	h.pubs.RecordAccess(r.Context(), pub)
+	h.files.Serve(w, r, pub.FilePath)
+}
+
+// getFileByPublicationID handles GET and HEAD /v1/files/{uuid}: serves a
+// publication's file by its own id, for clients that already hold a
+// publication uuid and don't want the extra round trip through
+// /v1/publications/{uuid}/file's redirect to the content-addressed form. A
+// HEAD request gets the same Content-Length/Content-Type/checksum/
+// disposition headers without the body, so a client with an upload size
+// limit (e.g. a Telegram bot) can decide whether to fetch at all.
+func (h *Handler) getFileByPublicationID(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	pub, err := h.pubs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if pub.FilePath == "" {
+		http.Error(w, "file no longer available", http.StatusGone)
+		return
+	}
+
+	sha256, err := h.pubs.EnsureFileHash(r.Context(), pub)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setFilePreviewHeaders(w, pub, sha256)
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	h.pubs.RecordAccess(r.Context(), pub)
+	h.files.Serve(w, r, pub.FilePath)
+}
+
+// setFilePreviewHeaders sets the headers a client needs to decide whether
+// to fetch pub's file, without requiring the body: its size, guessed MIME
+// type, content hash and a suggested filename for Content-Disposition.
+func setFilePreviewHeaders(w http.ResponseWriter, pub *entity.Publication, sha256 string) {
+	name := filepath.Base(pub.FilePath)
+
+	w.Header().Set("Content-Length", strconv.FormatInt(pub.FileSizeBytes, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename=%q`, name))
+	w.Header().Set("X-Content-SHA256", sha256)
+
+	if ct := mime.TypeByExtension(filepath.Ext(name)); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+}
+
+// getFileInfo handles GET /v1/files/{id}/info: returns the served file's
+// size and content hash without transferring the file itself, so a client
+// can decide whether to fetch or resume before paying for the download. id
+// must be a publication uuid; the content-hash form has no info route since
+// the hash itself already answers that question.
+func (h *Handler) getFileInfo(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid publication uuid", http.StatusBadRequest)
+		return
+	}
+
+	pub, err := h.pubs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if pub.FilePath == "" {
+		http.Error(w, "file no longer available", http.StatusGone)
+		return
+	}
+
+	sha256, err := h.pubs.EnsureFileHash(r.Context(), pub)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		SizeBytes int64  `json:"size_bytes"`
+		SHA256    string `json:"sha256"`
+	}{SizeBytes: pub.FileSizeBytes, SHA256: sha256})
+}
+
+// getSprite handles GET /v1/publications/{uuid}/sprite: serves the preview
+// strip generated for this publication's video, if any, with long-lived
+// cache headers since finished artifacts never change.
+func (h *Handler) getSprite(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("uuid"))
+	if err != nil {
+		http.Error(w, "invalid publication uuid", http.StatusBadRequest)
+		return
+	}
+
+	pub, err := h.pubs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	sprite, ok := pub.ArtifactByType(entity.ArtifactTypeSprite)
+	if !ok {
+		http.Error(w, "no sprite generated for this publication", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeFile(w, r, sprite.FilePath)
+}
+
+// getThumbnail handles GET /v1/publications/{uuid}/thumbnail: serves a
+// single cover-frame image for the publication, generating one via ffmpeg
+// on first request if yt-dlp didn't already write one; see
+// PublicationService.EnsureThumbnail. The result is cached on disk, so
+// unlike getSprite this doesn't need its file to already exist.
+func (h *Handler) getThumbnail(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("uuid"))
+	if err != nil {
+		http.Error(w, "invalid publication uuid", http.StatusBadRequest)
+		return
+	}
+
+	pub, err := h.pubs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	path, err := h.pubs.EnsureThumbnail(r.Context(), pub)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeFile(w, r, path)
+}
+
+// getMetadata handles GET /v1/publications/{uuid}/metadata: returns the
+// chapters/description/tags/upload-date parsed from the extractor's info
+// JSON at download time.
+func (h *Handler) getMetadata(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("uuid"))
+	if err != nil {
+		http.Error(w, "invalid publication uuid", http.StatusBadRequest)
+		return
+	}
+
+	pub, err := h.pubs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	h.pubs.EnrichMetadata(r.Context(), pub)
+
+	if pub.Metadata == nil {
+		http.Error(w, "no metadata recorded for this publication", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pub.Metadata)
+}
+
+// getInfoJSON handles GET /v1/publications/{uuid}/info.json: serves the raw
+// extractor info JSON, transparently decompressing it from its gzip-backed
+// storage.
+func (h *Handler) getInfoJSON(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("uuid"))
+	if err != nil {
+		http.Error(w, "invalid publication uuid", http.StatusBadRequest)
+		return
+	}
+
+	pub, err := h.pubs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if pub.InfoJSONPath == "" {
+		http.Error(w, "no info json retained for this publication", http.StatusNotFound)
+		return
+	}
+
+	f, err := os.Open(pub.InfoJSONPath)
+	if err != nil {
+		http.Error(w, "info json unavailable", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		http.Error(w, "corrupt info json", http.StatusInternalServerError)
+		return
+	}
+	defer gz.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.Copy(w, gz)
+}
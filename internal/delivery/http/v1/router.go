@@ -0,0 +1,216 @@
+// Package v1 wires the v1 REST API routes to the service layer.
+package v1
+
+import (
+	"net/http"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/middleware"
+	"github.com/evan-sm/daunrodo/internal/service"
+)
+
+// Handler groups the dependencies needed by the v1 route handlers.
+type Handler struct {
+	jobs        *service.JobService
+	pubs        *service.PublicationService
+	imports     *service.ImportService
+	archive     *service.ArchiveService
+	scheduler   *service.SchedulerService
+	support     *service.SupportBundleService
+	purge       *service.PurgeService
+	files       FileServer
+	hub         *service.Hub
+	keyDefaults *service.KeyDefaultsService
+	// auth, if non-nil, gates each route group by scope; see
+	// entity.APIKey and the scoped helper. nil leaves every route open,
+	// same as before AuthService existed.
+	auth *service.AuthService
+	// enqueueLimit, if set, wraps the job-enqueueing routes to cap how
+	// often a single client can submit jobs; see middleware.RateLimit.
+	enqueueLimit func(http.Handler) http.Handler
+	// downloadLimit, if set, wraps the file-streaming route to cap how many
+	// concurrent downloads a single client may hold open; see
+	// middleware.ConcurrencyLimit.
+	downloadLimit func(http.Handler) http.Handler
+	// fileWriteLimit, if set, wraps the file-serving routes with a longer
+	// response write deadline than the server-wide default, so a slow
+	// client on a large file doesn't get cut off mid-download; see
+	// middleware.WriteTimeout and config.Server.FileWriteTimeout.
+	fileWriteLimit func(http.Handler) http.Handler
+	// queryEnqueueEnabled registers GET /v1/jobs/enqueue, accepting
+	// enqueue parameters as query-string values; see
+	// config.Server.EnqueueViaQueryEnabled.
+	queryEnqueueEnabled bool
+	// allowPrivateWebhookHosts is forwarded to service.ValidateWebhookURL
+	// when validating a caller-supplied webhook URL (enqueue, share,
+	// /v1/me/defaults); see config.Webhook.AllowPrivateHosts.
+	allowPrivateWebhookHosts bool
+	// prefix is the path Register mounted the handler's routes under, set
+	// by Register itself; Manifest reads it back to build absolute action
+	// URLs for the share target, and WebUI reads it back so the embedded
+	// page knows where to call the API.
+	prefix string
+	// routes records every route Register mounts, in registration order,
+	// so GET /v1/openapi.json can generate documentation that can never
+	// drift from what's actually mounted: route (and routeOpen) is
+	// Register's only path to mux.Handle, so every entry here is also on
+	// the mux and vice versa. See openapi.go.
+	routes []apiRoute
+}
+
+// NewHandler builds the v1 Handler. files controls how finished artifacts
+// are served, see FileServer. hub feeds the /v1/ws subscribe action.
+// enqueueLimit, downloadLimit and fileWriteLimit may be nil to leave the
+// respective routes unlimited/at the server-wide default. queryEnqueueEnabled
+// registers GET /v1/jobs/enqueue; see config.Server.EnqueueViaQueryEnabled.
+// keyDefaults backs GET/PUT /v1/me/defaults. auth may be nil to leave every
+// route open; see service.AuthService. allowPrivateWebhookHosts is
+// forwarded to service.ValidateWebhookURL; see
+// config.Webhook.AllowPrivateHosts.
+func NewHandler(jobs *service.JobService, pubs *service.PublicationService, imports *service.ImportService, archive *service.ArchiveService, scheduler *service.SchedulerService, support *service.SupportBundleService, purge *service.PurgeService, files FileServer, hub *service.Hub, enqueueLimit, downloadLimit, fileWriteLimit func(http.Handler) http.Handler, queryEnqueueEnabled bool, keyDefaults *service.KeyDefaultsService, auth *service.AuthService, allowPrivateWebhookHosts bool) *Handler {
+	return &Handler{jobs: jobs, pubs: pubs, imports: imports, archive: archive, scheduler: scheduler, support: support, purge: purge, files: files, hub: hub, enqueueLimit: enqueueLimit, downloadLimit: downloadLimit, fileWriteLimit: fileWriteLimit, queryEnqueueEnabled: queryEnqueueEnabled, keyDefaults: keyDefaults, auth: auth, allowPrivateWebhookHosts: allowPrivateWebhookHosts}
+}
+
+// Register mounts the v1 routes onto mux under the given prefix.
+func (h *Handler) Register(mux *http.ServeMux, prefix string) {
+	h.prefix = prefix
+
+	h.route(mux, "POST", prefix+"/jobs", entity.ScopeEnqueue, "Enqueue a download job", h.enqueueJob, h.limited)
+	if h.queryEnqueueEnabled {
+		h.route(mux, "GET", prefix+"/jobs/enqueue", entity.ScopeEnqueue, "Enqueue a download job via query string", h.enqueueJob, h.limited)
+	}
+	h.route(mux, "GET", prefix+"/share", entity.ScopeEnqueue, "Enqueue a download job from a share-sheet target", h.shareTarget, h.limited)
+	h.route(mux, "POST", prefix+"/share", entity.ScopeEnqueue, "Enqueue a download job from a share-sheet target", h.shareTarget, h.limited)
+	h.routeOpen(mux, "GET", prefix+"/me/defaults", "Get the caller's stored enqueue defaults", h.getMyDefaults)
+	h.routeOpen(mux, "PUT", prefix+"/me/defaults", "Set the caller's stored enqueue defaults", h.putMyDefaults)
+	h.route(mux, "POST", prefix+"/jobs/import", entity.ScopeEnqueue, "Enqueue jobs from an uploaded URL list", h.importJobs, h.limited)
+	h.route(mux, "GET", prefix+"/jobs", entity.ScopeRead, "List jobs", h.listJobs)
+	h.route(mux, "GET", prefix+"/jobs/{id}", entity.ScopeRead, "Get a job", h.getJob)
+	h.route(mux, "DELETE", prefix+"/jobs/{id}", entity.ScopeEnqueue, "Delete a job", h.deleteJob)
+	h.route(mux, "POST", prefix+"/jobs/purge", entity.ScopeEnqueue, "Bulk-delete jobs matching a filter", h.purgeJobs)
+	h.route(mux, "POST", prefix+"/jobs/probe", entity.ScopeEnqueue, "Probe a URL's metadata without downloading it", h.probeJob, h.limited)
+	h.route(mux, "POST", prefix+"/jobs/{id}/cancel", entity.ScopeEnqueue, "Cancel a job", h.cancelJob)
+	h.route(mux, "POST", prefix+"/jobs/{id}/pause", entity.ScopeEnqueue, "Pause a running job", h.pauseJob)
+	h.route(mux, "POST", prefix+"/jobs/{id}/resume", entity.ScopeEnqueue, "Resume a paused job", h.resumeJob)
+	h.route(mux, "GET", prefix+"/jobs/{id}/stream", entity.ScopeFiles, "Tail-follow a running job's in-progress output", h.getJobStream, h.downloadLimited, h.fileWriteLimited)
+	h.route(mux, "GET", prefix+"/jobs/{id}/summary", entity.ScopeRead, "Get a job's full summary", h.getJobSummary)
+	h.route(mux, "GET", prefix+"/jobs/{id}/logs", entity.ScopeRead, "Get a job's log lines", h.getJobLogs)
+	h.route(mux, "GET", prefix+"/jobs/{id}/runs", entity.ScopeRead, "Get a job's retry run history", h.getJobRuns)
+	h.route(mux, "GET", prefix+"/jobs/{id}/commands", entity.ScopeRead, "Get a job's audited downloader command lines", h.getJobCommands)
+	h.route(mux, "GET", prefix+"/jobs/{id}/publication", entity.ScopeRead, "Get the publication produced by a job", h.getJobPublication)
+	h.route(mux, "GET", prefix+"/publications", entity.ScopeRead, "List publications matching filters", h.listPublications)
+	h.route(mux, "GET", prefix+"/jobs/{id}/publications", entity.ScopeRead, "List the publications a job produced", h.getJobPublications)
+	h.route(mux, "GET", prefix+"/publications/{uuid}", entity.ScopeRead, "Get a publication", h.getPublication)
+	h.route(mux, "GET", prefix+"/publications/{uuid}/file", entity.ScopeFiles, "Redirect to a publication's content-addressed file", h.getPublicationFile)
+	h.route(mux, "GET", prefix+"/files/{id}/info", entity.ScopeRead, "Get a file's size and content hash", h.getFileInfo)
+	h.route(mux, "GET", prefix+"/files/{id}", entity.ScopeFiles, "Download a file by content hash or publication id", h.getFileByID, h.downloadLimited, h.fileWriteLimited)
+	h.route(mux, "POST", prefix+"/publications/{uuid}/refetch", entity.ScopeEnqueue, "Re-enqueue an expired publication's source URL", h.refetchPublication)
+	h.route(mux, "GET", prefix+"/publications/{uuid}/sprite", entity.ScopeRead, "Get a publication's scrub-preview sprite", h.getSprite)
+	h.route(mux, "GET", prefix+"/publications/{uuid}/thumbnail", entity.ScopeRead, "Get (generating if needed) a publication's cover thumbnail", h.getThumbnail)
+	h.route(mux, "GET", prefix+"/publications/{uuid}/metadata", entity.ScopeRead, "Get a publication's parsed extractor metadata", h.getMetadata)
+	h.route(mux, "GET", prefix+"/publications/{uuid}/info.json", entity.ScopeRead, "Get a publication's raw extractor info JSON", h.getInfoJSON)
+	h.route(mux, "POST", prefix+"/archive", entity.ScopeEnqueue, "Archive an entire account/channel", h.archiveAccount)
+	h.route(mux, "POST", prefix+"/archive/{id}/resume", entity.ScopeEnqueue, "Resume a paused archive operation", h.resumeArchive)
+	h.route(mux, "POST", prefix+"/groups/{id}/sync", entity.ScopeEnqueue, "Sync a job group (playlist/channel) for new items", h.syncGroup)
+	h.route(mux, "GET", prefix+"/ws", entity.ScopeRead, "Subscribe to job status updates over WebSocket", h.getWS)
+	h.route(mux, "POST", prefix+"/schedules", entity.ScopeEnqueue, "Create a recurring download schedule", h.createSchedule)
+	h.route(mux, "GET", prefix+"/schedules", entity.ScopeRead, "List schedules", h.listSchedules)
+	h.route(mux, "GET", prefix+"/schedules/{id}", entity.ScopeRead, "Get a schedule", h.getSchedule)
+	h.route(mux, "PUT", prefix+"/schedules/{id}", entity.ScopeEnqueue, "Update a schedule", h.updateSchedule)
+	h.route(mux, "DELETE", prefix+"/schedules/{id}", entity.ScopeEnqueue, "Delete a schedule", h.deleteSchedule)
+	h.route(mux, "GET", prefix+"/admin/support-bundle", entity.ScopeAdmin, "Download a diagnostics support bundle", h.getSupportBundle)
+	h.route(mux, "GET", prefix+"/admin/publications/stats", entity.ScopeAdmin, "Get aggregate publication download stats", h.getPublicationStats)
+	h.route(mux, "GET", prefix+"/admin/queue", entity.ScopeAdmin, "Get worker pool queue stats", h.getQueue)
+	h.route(mux, "GET", prefix+"/admin/workers", entity.ScopeAdmin, "Get per-worker status", h.getWorkers)
+	h.route(mux, "POST", prefix+"/admin/queue/drain", entity.ScopeAdmin, "Drain the worker pool", h.drainQueue)
+	h.route(mux, "POST", prefix+"/admin/purge", entity.ScopeAdmin, "Bulk-purge jobs/publications matching a filter", h.purgeData)
+
+	h.routeOpen(mux, "GET", prefix+"/openapi.json", "Get the OpenAPI document for this API", h.getOpenAPI)
+	h.routeOpen(mux, "GET", prefix+"/docs", "Get an interactive API explorer for this API", h.getDocs)
+	h.routeOpen(mux, "GET", prefix+"/examples", "Get curl/Python/Go request examples for this API", h.getExamples)
+}
+
+// route registers handler on mux for method+pattern, gated by scope (see
+// scoped), wrapped by wraps from innermost to outermost, and records the
+// route in h.routes for documentation. This is Register's only path to
+// mux.Handle for an authenticated route, so h.routes can never drift from
+// what's actually mounted; see openapi.go.
+func (h *Handler) route(mux *http.ServeMux, method, pattern, scope, summary string, handler http.HandlerFunc, wraps ...func(http.Handler) http.Handler) {
+	var hdl http.Handler = h.scoped(scope, handler)
+
+	for _, wrap := range wraps {
+		hdl = wrap(hdl)
+	}
+
+	mux.Handle(method+" "+pattern, hdl)
+	h.routes = append(h.routes, apiRoute{Method: method, Pattern: pattern, Scope: scope, Summary: summary})
+}
+
+// routeOpen registers handler on mux for method+pattern with no scope
+// check, for routes meant to stay reachable without an API key (or, like
+// openapi.json/docs, that simply have nothing to protect). Records the
+// route in h.routes like route does.
+func (h *Handler) routeOpen(mux *http.ServeMux, method, pattern, summary string, handler http.HandlerFunc) {
+	mux.HandleFunc(method+" "+pattern, handler)
+	h.routes = append(h.routes, apiRoute{Method: method, Pattern: pattern, Summary: summary})
+}
+
+// scoped wraps next so it 401s a request with no API key and 403s one
+// whose key doesn't grant scope, per h.auth; see entity.APIKey.HasScope. A
+// nil h.auth (or one with no keys file loaded) leaves next unchanged, same
+// as today's no-auth default.
+func (h *Handler) scoped(scope string, next http.HandlerFunc) http.Handler {
+	if h.auth == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.auth.Configured() {
+			next(w, r)
+			return
+		}
+
+		key := r.Header.Get(middleware.ClientKeyHeader)
+		if key == "" {
+			http.Error(w, "missing "+middleware.ClientKeyHeader+" header", http.StatusUnauthorized)
+			return
+		}
+
+		if !h.auth.Authorize(key, scope) {
+			http.Error(w, "key lacks required scope: "+scope, http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	})
+}
+
+// limited wraps next in enqueueLimit if one is configured, otherwise
+// returns it unchanged.
+func (h *Handler) limited(next http.Handler) http.Handler {
+	if h.enqueueLimit == nil {
+		return next
+	}
+
+	return h.enqueueLimit(next)
+}
+
+// downloadLimited wraps next in downloadLimit if one is configured,
+// otherwise returns it unchanged.
+func (h *Handler) downloadLimited(next http.Handler) http.Handler {
+	if h.downloadLimit == nil {
+		return next
+	}
+
+	return h.downloadLimit(next)
+}
+
+// fileWriteLimited wraps next in fileWriteLimit if one is configured,
+// otherwise returns it unchanged; see Handler.fileWriteLimit.
+func (h *Handler) fileWriteLimited(next http.Handler) http.Handler {
+	if h.fileWriteLimit == nil {
+		return next
+	}
+
+	return h.fileWriteLimit(next)
+}
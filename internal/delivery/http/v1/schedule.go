@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type scheduleRequest struct {
+	URL     string `json:"url"`
+	Preset  string `json:"preset"`
+	Cron    string `json:"cron"`
+	Enabled bool   `json:"enabled"`
+}
+
+// createSchedule handles POST /v1/schedules: registers url/preset to be
+// re-probed and expanded into child jobs on cron.
+func (h *Handler) createSchedule(w http.ResponseWriter, r *http.Request) {
+	var req scheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.scheduler.Create(r.Context(), req.URL, req.Preset, req.Cron)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(schedule)
+}
+
+// listSchedules handles GET /v1/schedules.
+func (h *Handler) listSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.scheduler.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(schedules)
+}
+
+// getSchedule handles GET /v1/schedules/{id}.
+func (h *Handler) getSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid schedule id", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.scheduler.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(schedule)
+}
+
+// updateSchedule handles PUT /v1/schedules/{id}: partially updates url,
+// preset and cron (empty fields keep their current value) and sets
+// Enabled.
+func (h *Handler) updateSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid schedule id", http.StatusBadRequest)
+		return
+	}
+
+	var req scheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.scheduler.Update(r.Context(), id, req.URL, req.Preset, req.Cron, req.Enabled)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(schedule)
+}
+
+// deleteSchedule handles DELETE /v1/schedules/{id}.
+func (h *Handler) deleteSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid schedule id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.scheduler.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// extractSharedURL finds the first http(s) URL embedded in rawURL, text or
+// title, in that order, to pull the shared link out of whichever field
+// Android's share sheet put it in: often a dedicated "url" field, but
+// sometimes buried inside "text" alongside other shared content.
+func extractSharedURL(title, text, rawURL string) string {
+	for _, s := range []string{rawURL, text, title} {
+		if u := firstURL(s); u != "" {
+			return u
+		}
+	}
+
+	return ""
+}
+
+// firstURL returns the first http(s):// substring in s, up to the next
+// whitespace, or "" if none is found.
+func firstURL(s string) string {
+	for _, scheme := range []string{"https://", "http://"} {
+		idx := strings.Index(s, scheme)
+		if idx < 0 {
+			continue
+		}
+
+		rest := s[idx:]
+		if end := strings.IndexAny(rest, " \t\n\r"); end >= 0 {
+			rest = rest[:end]
+		}
+
+		return rest
+	}
+
+	return ""
+}
+
+// shareTarget handles GET/POST /v1/share: the action endpoint registered in
+// the PWA manifest's share_target, letting a user share a link from any
+// Android app straight into daunrodo. It accepts the same title/text/url
+// fields the Web Share Target API delivers, as query parameters (GET) or a
+// form body (POST), pulls a source URL out of them and enqueues a job with
+// the default preset, then redirects to the new job's resource so the
+// client ends up looking at its progress.
+func (h *Handler) shareTarget(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	v := r.Form
+	if r.Method == http.MethodPost {
+		v = r.PostForm
+	}
+
+	url := extractSharedURL(v.Get("title"), v.Get("text"), v.Get("url"))
+	if url == "" {
+		http.Error(w, "no shareable url found", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Enqueue(r.Context(), url, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, h.prefix+"/jobs/"+job.ID.String(), http.StatusSeeOther)
+}
+
+// Manifest serves a minimal Web App Manifest advertising daunrodo's share
+// target, so an Android user can register it as a share destination for
+// links shared from any app. There's no installable PWA shell (icons,
+// service worker, start page) behind it yet; this exists to make the OS
+// picker entry and the /share action work today, ahead of one. It is
+// mounted at the site root (not under the versioned /v1 prefix) since a
+// manifest isn't itself part of the API; see cmd/daunrodo.
+func (h *Handler) Manifest(w http.ResponseWriter, r *http.Request) {
+	m := map[string]any{
+		"name":       "daunrodo",
+		"short_name": "daunrodo",
+		"display":    "standalone",
+		"start_url":  h.prefix + "/jobs",
+		"share_target": map[string]any{
+			"action": h.prefix + "/share",
+			"method": "GET",
+			"params": map[string]string{
+				"title": "title",
+				"text":  "text",
+				"url":   "url",
+			},
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/manifest+json")
+	_ = json.NewEncoder(w).Encode(m)
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// streamPollInterval is how often getJobStream rechecks the in-progress
+// output file for new bytes once it catches up to the downloader, and
+// rechecks the job's own status to know when to stop.
+const streamPollInterval = 500 * time.Millisecond
+
+// getJobStream handles GET /v1/jobs/{id}/stream: tail-follows the output
+// file yt-dlp is currently writing for a running job over a chunked
+// response, so a client (e.g. a video player) can start playback before
+// the job finishes instead of waiting for it to land behind /v1/files.
+// Requires YtDlpRunner.JobDir to still know the job's temp directory, so
+// this only works while the job is JobStatusRunning; once it's done, use
+// the regular file routes instead.
+func (h *Handler) getJobStream(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if job.Status() != entity.JobStatusRunning {
+		http.Error(w, "job is not currently running; see /v1/files once it finishes", http.StatusConflict)
+		return
+	}
+
+	dir, ok := h.jobs.RunnerOutputDir(id)
+	if !ok {
+		http.Error(w, "no in-progress output found for this job", http.StatusNotFound)
+		return
+	}
+
+	path, err := largestRegularFile(dir)
+	if err != nil {
+		http.Error(w, "no downloaded bytes yet, try again shortly", http.StatusNotFound)
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	sniff := make([]byte, 512)
+
+	n, _ := f.Read(sniff)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", http.DetectContentType(sniff[:n]))
+	w.WriteHeader(http.StatusOK)
+
+	tailFollow(r.Context(), w, f, func() bool {
+		current, err := h.jobs.Get(r.Context(), id)
+		return err == nil && current.Status() == entity.JobStatusRunning
+	})
+}
+
+// largestRegularFile returns the biggest regular file directly under dir,
+// a best-effort guess at which of yt-dlp's working files (format
+// fragments, thumbnails, the eventual merged output) is the one worth
+// streaming; yt-dlp exposes no structured way to ask which file is the
+// "real" output while a job is still running.
+func largestRegularFile(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		best     string
+		bestSize int64
+	)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		if info.Size() > bestSize {
+			best = filepath.Join(dir, entry.Name())
+			bestSize = info.Size()
+		}
+	}
+
+	if best == "" {
+		return "", os.ErrNotExist
+	}
+
+	return best, nil
+}
+
+// tailFollow copies f's contents to w as they're written, flushing after
+// every read so the client sees bytes as they land rather than buffered up.
+// It polls for new data every streamPollInterval, stopping once stillLive
+// reports false (the job left JobStatusRunning) or ctx is cancelled (the
+// client disconnected), after one last drain to pick up whatever was
+// written since the previous poll.
+func tailFollow(ctx context.Context, w http.ResponseWriter, f *os.File, stillLive func() bool) {
+	flusher, _ := w.(http.Flusher)
+
+	drain := func() {
+		if _, err := io.Copy(w, f); err == nil && flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	for {
+		drain()
+
+		if !stillLive() {
+			drain()
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamPollInterval):
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// summaryLocale holds the translated words used to build a job summary line.
+// Only the handful of languages bot frontends have actually asked for are
+// listed here; unknown locales fall back to English.
+type summaryLocale struct {
+	queued  string
+	running string
+	error   string
+	by      string
+}
+
+var summaryLocales = map[string]summaryLocale{
+	"en": {queued: "queued", running: "downloading", error: "error", by: "by"},
+	"ru": {queued: "в очереди", running: "загружается", error: "ошибка", by: "автор"},
+	"es": {queued: "en cola", running: "descargando", error: "error", by: "por"},
+}
+
+// resolveSummaryLocale picks a locale from the request's lang query param or
+// Accept-Language header, defaulting to English.
+func resolveSummaryLocale(r *http.Request) summaryLocale {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = r.Header.Get("Accept-Language")
+	}
+
+	lang = strings.ToLower(strings.SplitN(strings.TrimSpace(lang), "-", 2)[0])
+
+	if loc, ok := summaryLocales[lang]; ok {
+		return loc
+	}
+
+	return summaryLocales["en"]
+}
+
+// getJobSummary returns a single compact, human-readable line describing
+// job's progress or result, meant for chat-bot frontends that would
+// otherwise each reimplement this formatting.
+func (h *Handler) getJobSummary(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var pub *entity.Publication
+	if job.Status() == entity.JobStatusDone {
+		pub, _ = h.pubs.GetByJob(r.Context(), job.ID)
+	}
+
+	loc := resolveSummaryLocale(r)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprintln(w, jobSummaryLine(job, pub, loc))
+}
+
+// jobSummaryLine formats job (and its publication, if any) as one line.
+func jobSummaryLine(job *entity.Job, pub *entity.Publication, loc summaryLocale) string {
+	switch job.Status() {
+	case entity.JobStatusPending, entity.JobStatusQueued:
+		return "⏳ " + loc.queued + " — " + job.URL
+	case entity.JobStatusRunning:
+		return "🔄 " + loc.running + " — " + job.URL
+	case entity.JobStatusError:
+		return "❌ " + loc.error + ": " + job.Error()
+	case entity.JobStatusCancelled:
+		return "🚫 " + job.URL
+	case entity.JobStatusDone:
+		return "✅ " + doneSummary(pub, loc)
+	default:
+		return job.URL
+	}
+}
+
+// doneSummary formats the finished portion of a summary line, degrading
+// gracefully when pub or its optional fields are unset.
+func doneSummary(pub *entity.Publication, loc summaryLocale) string {
+	if pub == nil {
+		return "done"
+	}
+
+	var parts []string
+
+	if pub.Resolution != "" {
+		parts = append(parts, pub.Resolution)
+	}
+
+	if pub.FileSizeBytes > 0 {
+		parts = append(parts, formatBytes(pub.FileSizeBytes))
+	}
+
+	if pub.Duration > 0 {
+		parts = append(parts, formatDuration(pub.Duration))
+	}
+
+	line := strings.Join(parts, ", ")
+
+	title := pub.Title
+	if pub.Author != "" {
+		title = fmt.Sprintf("%s %s %s", title, loc.by, pub.Author)
+	}
+
+	if line == "" {
+		return title
+	}
+
+	return line + " — " + title
+}
+
+// formatDuration renders d as e.g. "3m12s", dropping the hours component
+// when zero.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+
+	if h > 0 {
+		return fmt.Sprintf("%dh%dm%ds", h, m, s)
+	}
+
+	return fmt.Sprintf("%dm%ds", m, s)
+}
+
+// formatBytes renders n bytes as a human "MB"/"GB" string.
+func formatBytes(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,7 @@
+package v1
+
+import "github.com/google/uuid"
+
+func parseUUID(s string) (uuid.UUID, error) {
+	return uuid.Parse(s)
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/service"
+)
+
+// fieldError is a single field-level problem found while validating an
+// enqueue request; see validateEnqueue.
+type fieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// validationErrors wraps every fieldError found in a single request. It's
+// returned as the body of a 400 response so a caller fixes every problem
+// at once instead of round-tripping one field error at a time.
+type validationErrors struct {
+	Errors []fieldError `json:"errors"`
+}
+
+func (v validationErrors) add(field, code, message string) validationErrors {
+	v.Errors = append(v.Errors, fieldError{Field: field, Code: code, Message: message})
+
+	return v
+}
+
+// validateEnqueue checks an enqueue request's fields up front (URL, clip
+// section bounds, the webhook callback URL and label tags), collecting
+// every problem it finds rather than stopping at the first one. Preset is
+// optional: an omitted one is resolved later by
+// JobService.defaultPresetForURL. allowPrivateWebhookHosts is forwarded to
+// service.ValidateWebhookURL; see config.Webhook.AllowPrivateHosts.
+func validateEnqueue(req enqueueRequest, allowPrivateWebhookHosts bool) validationErrors {
+	var v validationErrors
+
+	if strings.TrimSpace(req.URL) == "" {
+		v = v.add("url", "required", "url is required")
+	} else if u, err := url.Parse(req.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		v = v.add("url", "invalid", "url must be an absolute http(s) URL")
+	}
+
+	if req.Start != "" {
+		if _, err := time.ParseDuration(req.Start); err != nil {
+			v = v.add("start", "invalid", `start must be a duration, e.g. "30s"`)
+		}
+	}
+
+	if req.End != "" {
+		if _, err := time.ParseDuration(req.End); err != nil {
+			v = v.add("end", "invalid", `end must be a duration, e.g. "1m30s"`)
+		}
+	}
+
+	if req.Webhook != "" {
+		if err := service.ValidateWebhookURL(req.Webhook, allowPrivateWebhookHosts); err != nil {
+			v = v.add("webhook", "invalid", err.Error())
+		}
+	}
+
+	for key := range req.Labels {
+		if strings.TrimSpace(key) == "" {
+			v = v.add("labels", "invalid", "label keys must not be empty")
+
+			break
+		}
+	}
+
+	if req.MaxRateKbps < 0 {
+		v = v.add("max_rate_kbps", "invalid", "max_rate_kbps must not be negative")
+	}
+
+	return v
+}
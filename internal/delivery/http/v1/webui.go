@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"bytes"
+	"embed"
+	"net/http"
+)
+
+// webFS embeds the single-page UI served at GET /; see WebUI. Built into
+// the binary at compile time, so serving it needs no external build step
+// or filesystem access at runtime.
+//
+//go:embed web
+var webFS embed.FS
+
+// WebUI handles GET /: a small embedded single-page app for pasting a
+// URL, picking a preset, enqueueing a job and watching its progress live
+// over /v1/ws, then opening the finished file once it's done. Registered
+// directly on the top-level mux by cmd/daunrodo, the same way Manifest is,
+// since it sits outside the versioned /v1 API surface.
+func (h *Handler) WebUI(w http.ResponseWriter, r *http.Request) {
+	page, err := webFS.ReadFile("web/index.html")
+	if err != nil {
+		http.Error(w, "web ui not available", http.StatusInternalServerError)
+		return
+	}
+
+	page = bytes.ReplaceAll(page, []byte("__API_PREFIX__"), []byte(h.prefix))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(page)
+}
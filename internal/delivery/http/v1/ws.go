@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/middleware"
+	"github.com/evan-sm/daunrodo/internal/service"
+	"github.com/evan-sm/daunrodo/internal/ws"
+)
+
+// wsRequest is a single client->server control message on /v1/ws.
+type wsRequest struct {
+	Action string `json:"action"` // "enqueue", "subscribe" or "cancel"
+	// Enqueue fields, used when Action == "enqueue".
+	enqueueRequest
+	// JobID is used when Action is "subscribe" or "cancel".
+	JobID string `json:"job_id"`
+}
+
+// wsResponse is a single server->client message on /v1/ws: either a direct
+// reply to a request, or a subscribed job's status update.
+type wsResponse struct {
+	Action string      `json:"action"`
+	Job    *entity.Job `json:"job,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// getWS handles GET /v1/ws: upgrades to a WebSocket where clients can
+// enqueue jobs, subscribe to status updates for specific job UUIDs, and
+// cancel jobs, all as JSON control messages.
+func (h *Handler) getWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := ws.Upgrade(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer conn.Close()
+
+	var unsubscribes []func()
+	defer func() {
+		for _, unsub := range unsubscribes {
+			unsub()
+		}
+	}()
+
+	for {
+		raw, err := conn.ReadMessage()
+		if err != nil {
+			if !errors.Is(err, ws.ErrClosed) {
+				slog.Default().Debug("ws: read failed", "error", err)
+			}
+
+			return
+		}
+
+		var req wsRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			h.wsSend(conn, wsResponse{Action: "error", Error: "invalid message: " + err.Error()})
+			continue
+		}
+
+		switch req.Action {
+		case "enqueue":
+			h.wsEnqueue(conn, r, req)
+		case "cancel":
+			h.wsCancel(conn, r, req)
+		case "subscribe":
+			if unsub := h.wsSubscribe(conn, r, req); unsub != nil {
+				unsubscribes = append(unsubscribes, unsub)
+			}
+		default:
+			h.wsSend(conn, wsResponse{Action: "error", Error: "unknown action: " + req.Action})
+		}
+	}
+}
+
+func (h *Handler) wsEnqueue(conn *ws.Conn, r *http.Request, req wsRequest) {
+	if !h.wsAuthorizeEnqueue(conn, r, "enqueue") {
+		return
+	}
+
+	opts := service.EnqueueOptions{URL: req.URL, Preset: req.Preset, Profile: req.Profile, Webhook: req.Webhook, Submitter: middleware.ClientIdentity(r)}
+
+	job, err := h.jobs.EnqueueWithOptions(r.Context(), opts)
+	if err != nil {
+		h.wsSend(conn, wsResponse{Action: "enqueue", Error: err.Error()})
+		return
+	}
+
+	h.wsSend(conn, wsResponse{Action: "enqueue", Job: job})
+}
+
+func (h *Handler) wsCancel(conn *ws.Conn, r *http.Request, req wsRequest) {
+	if !h.wsAuthorizeEnqueue(conn, r, "cancel") {
+		return
+	}
+
+	id, err := parseUUID(req.JobID)
+	if err != nil {
+		h.wsSend(conn, wsResponse{Action: "cancel", Error: "invalid job_id"})
+		return
+	}
+
+	job, err := h.jobs.Cancel(r.Context(), id)
+	if err != nil {
+		h.wsSend(conn, wsResponse{Action: "cancel", Error: err.Error()})
+		return
+	}
+
+	h.wsSend(conn, wsResponse{Action: "cancel", Job: job})
+}
+
+// wsAuthorizeEnqueue re-applies the entity.ScopeEnqueue check that POST
+// /v1/jobs and POST /v1/jobs/{id}/cancel enforce (see route's h.scoped),
+// and the enqueue rate limit that POST /v1/jobs enforces (h.limited); it
+// applies that rate limit to both control messages. getWS itself only requires entity.ScopeRead
+// so that read-only keys can subscribe to job updates; without this, an
+// "enqueue"/"cancel" control message would let a read-only key do what its
+// scope forbids, and skip rate limiting besides, just by going through the
+// WebSocket instead of the REST route. Sends a wsResponse error under
+// action and returns false if either check fails.
+func (h *Handler) wsAuthorizeEnqueue(conn *ws.Conn, r *http.Request, action string) bool {
+	if h.auth != nil && h.auth.Configured() {
+		key := r.Header.Get(middleware.ClientKeyHeader)
+		if key == "" || !h.auth.Authorize(key, entity.ScopeEnqueue) {
+			h.wsSend(conn, wsResponse{Action: action, Error: "key lacks required scope: " + entity.ScopeEnqueue})
+			return false
+		}
+	}
+
+	if h.enqueueLimit != nil {
+		resp := &wsLimiterResponse{}
+		h.limited(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(resp, r)
+
+		if resp.status == http.StatusTooManyRequests {
+			h.wsSend(conn, wsResponse{Action: action, Error: "rate limit exceeded"})
+			return false
+		}
+	}
+
+	return true
+}
+
+// wsLimiterResponse is a throwaway http.ResponseWriter for running
+// h.limited against an in-band WebSocket control message: by the time
+// wsEnqueue/wsCancel run, ws.Upgrade has already hijacked the original
+// ResponseWriter, so there's nothing real to pass the rate limiter's
+// middleware. It only needs to capture the status code the middleware
+// would have sent.
+type wsLimiterResponse struct {
+	header http.Header
+	status int
+}
+
+func (w *wsLimiterResponse) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+
+	return w.header
+}
+
+func (w *wsLimiterResponse) Write(b []byte) (int, error) { return len(b), nil }
+
+func (w *wsLimiterResponse) WriteHeader(status int) { w.status = status }
+
+// wsSubscribe forwards job status updates to conn until the connection's
+// read loop exits (via its deferred unsubscribe) or the hub channel closes.
+// It returns the unsubscribe func for the caller to invoke on shutdown.
+func (h *Handler) wsSubscribe(conn *ws.Conn, r *http.Request, req wsRequest) func() {
+	id, err := parseUUID(req.JobID)
+	if err != nil {
+		h.wsSend(conn, wsResponse{Action: "subscribe", Error: "invalid job_id"})
+		return nil
+	}
+
+	updates, unsubscribe := h.hub.Subscribe(id)
+
+	go func() {
+		for job := range updates {
+			h.wsSend(conn, wsResponse{Action: "update", Job: &job})
+		}
+	}()
+
+	return unsubscribe
+}
+
+func (h *Handler) wsSend(conn *ws.Conn, resp wsResponse) {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+
+	_ = conn.WriteMessage(body)
+}
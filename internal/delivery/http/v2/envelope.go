@@ -0,0 +1,44 @@
+package v2
+
+// Envelope is the v2 response shape: every endpoint returns its payload
+// under Data, with Meta carrying pagination for list endpoints. v1 returns
+// bare JSON bodies; v2 standardizes on this envelope so clients can parse
+// every response the same way regardless of route.
+type Envelope struct {
+	Data any   `json:"data"`
+	Meta *Meta `json:"meta,omitempty"`
+}
+
+// Meta carries pagination for a list endpoint's response.
+type Meta struct {
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	Total   int `json:"total"`
+}
+
+// paginate slices items to the given 1-based page of perPage items,
+// clamping out-of-range values to sane defaults, and returns the page
+// alongside Meta describing it.
+func paginate[T any](items []T, page, perPage int) ([]T, *Meta) {
+	if perPage <= 0 {
+		perPage = 20
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	total := len(items)
+
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	return items[start:end], &Meta{Page: page, PerPage: perPage, Total: total}
+}
@@ -0,0 +1,79 @@
+// Package v2 wires the v2 REST API routes to the service layer. Unlike v1,
+// every response is wrapped in Envelope so breaking response-shape changes
+// (pagination, future fields on Meta) can land here without touching v1's
+// frozen contract.
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/evan-sm/daunrodo/internal/service"
+	"github.com/google/uuid"
+)
+
+// Handler groups the dependencies needed by the v2 route handlers. It
+// depends directly on the service layer, the same way v1.Handler does,
+// rather than wrapping v1.Handler: v1's handler methods are unexported, so
+// the two packages stay structural siblings instead of one layering on the
+// other.
+type Handler struct {
+	jobs *service.JobService
+}
+
+// NewHandler builds the v2 Handler.
+func NewHandler(jobs *service.JobService) *Handler {
+	return &Handler{jobs: jobs}
+}
+
+// Register mounts the v2 routes onto mux under the given prefix.
+func (h *Handler) Register(mux *http.ServeMux, prefix string) {
+	mux.HandleFunc("GET "+prefix+"/jobs", h.listJobs)
+	mux.HandleFunc("GET "+prefix+"/jobs/{id}", h.getJob)
+}
+
+// listJobs handles GET /v2/jobs: lists jobs (optionally filtered by
+// ?label=key:value, as in v1) and returns them as a paginated Envelope,
+// page and per_page taken from the matching query params.
+func (h *Handler) listJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.jobs.List(r.Context(), r.URL.Query().Get("label"))
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+
+	pageItems, meta := paginate(jobs, page, perPage)
+
+	writeEnvelope(w, pageItems, meta)
+}
+
+func (h *Handler) getJob(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err, http.StatusNotFound)
+		return
+	}
+
+	writeEnvelope(w, job, nil)
+}
+
+func writeEnvelope(w http.ResponseWriter, data any, meta *Meta) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(Envelope{Data: data, Meta: meta})
+}
+
+func writeError(w http.ResponseWriter, err error, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Envelope{Data: map[string]string{"error": err.Error()}})
+}
@@ -0,0 +1,45 @@
+package depmanager
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// archAliases maps a kernel-reported "uname -m" value to the GOARCH name
+// Dependency.InstallURLs keys are expected to use.
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+	"armv7l":  "arm",
+	"i686":    "386",
+}
+
+// ExecArch returns the architecture the process is actually running under,
+// which can differ from runtime.GOARCH when the binary runs under
+// emulation, e.g. an amd64 image started on an arm64 host via Docker's
+// --platform flag and QEMU. runtime.GOARCH only reports what the binary
+// was compiled for, not what the kernel underneath it is; this shells out
+// to "uname -m" to ask the kernel directly. If uname is unavailable or
+// reports something unrecognized, it falls back to runtime.GOARCH.
+func ExecArch(ctx context.Context) string {
+	out, err := exec.CommandContext(ctx, "uname", "-m").Output()
+	if err != nil {
+		return runtime.GOARCH
+	}
+
+	kernel := strings.TrimSpace(string(bytes.TrimSpace(out)))
+	if arch, ok := archAliases[kernel]; ok {
+		return arch
+	}
+
+	return runtime.GOARCH
+}
+
+// Emulated reports whether the process is running under emulation, i.e. its
+// compiled runtime.GOARCH differs from the kernel's own; see ExecArch.
+func Emulated(ctx context.Context) bool {
+	return ExecArch(ctx) != runtime.GOARCH
+}
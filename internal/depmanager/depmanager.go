@@ -0,0 +1,117 @@
+// Package depmanager fetches and caches optional external tool binaries
+// (whisper.cpp, ffmpeg plugins, ...) that daunrodo shells out to, so
+// operators don't have to bake every optional dependency into the base
+// image.
+package depmanager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/evan-sm/daunrodo/pkg/retry"
+)
+
+// downloadRetry governs how many times a failed dependency download is
+// retried before Ensure gives up; a plain network blip shouldn't force an
+// operator to restart the server just to retry once.
+var downloadRetry = retry.Policy{MaxAttempts: 3, Base: time.Second}
+
+// Dependency describes a single installable binary.
+type Dependency struct {
+	Name       string
+	BinaryName string
+	InstallURL string // direct download URL for the current platform's binary
+	// InstallURLs, if non-empty, picks InstallURL by the process's actual
+	// execution architecture (see ExecArch) instead of a single fixed URL,
+	// keyed by GOARCH name (e.g. "amd64", "arm64"). A miss falls back to
+	// InstallURL, so it only needs entries for architectures that actually
+	// ship a different binary.
+	InstallURLs map[string]string
+}
+
+// Manager ensures dependencies are present under Dir, downloading them on
+// first use.
+type Manager struct {
+	Dir string
+	log *slog.Logger
+}
+
+// NewManager builds a Manager that caches binaries under dir. log receives
+// a warning the first time a dependency is resolved under emulation (see
+// Emulated), so operators running an amd64 image on an arm64 host via QEMU
+// know why downloads are slow or why a dependency prefers its arm64 URL; it
+// may be nil to disable the warning.
+func NewManager(dir string, log *slog.Logger) *Manager {
+	return &Manager{Dir: dir, log: log}
+}
+
+// Ensure returns the local path to dep's binary, downloading it from
+// whichever of dep.InstallURLs matches the process's actual execution
+// architecture, or dep.InstallURL otherwise, if it isn't already cached
+// under m.Dir; see ExecArch.
+func (m *Manager) Ensure(ctx context.Context, dep Dependency) (string, error) {
+	path := filepath.Join(m.Dir, dep.BinaryName)
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if m.log != nil && Emulated(ctx) {
+		m.log.Warn("dependency resolved under emulation", "dependency", dep.Name, "exec_arch", ExecArch(ctx), "compiled_arch", runtime.GOARCH)
+	}
+
+	url := dep.InstallURL
+	if arch := ExecArch(ctx); dep.InstallURLs[arch] != "" {
+		url = dep.InstallURLs[arch]
+	}
+
+	if url == "" {
+		return "", fmt.Errorf("dependency %s not installed and no install URL configured", dep.Name)
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("create dep dir: %w", err)
+	}
+
+	if err := retry.Do(ctx, downloadRetry, func(int) error {
+		return m.download(ctx, url, path)
+	}); err != nil {
+		return "", fmt.Errorf("install %s: %w", dep.Name, err)
+	}
+
+	return path, nil
+}
+
+func (m *Manager) download(ctx context.Context, url, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, resp.Body)
+
+	return err
+}
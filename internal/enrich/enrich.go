@@ -0,0 +1,59 @@
+// Package enrich calls official platform APIs (YouTube Data API, Reddit)
+// to enrich a publication's metadata with canonical fields an extractor's
+// own info JSON doesn't reliably report: exact publish date, license,
+// content category. See config.PlatformAPIs for how each platform is
+// independently enabled.
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/config"
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// ErrUnsupported is returned by Enrich when sourceURL's host has no
+// official platform API integration, or that platform's credentials
+// aren't configured.
+var ErrUnsupported = errors.New("enrich: platform not supported or not configured")
+
+// Service dispatches a source URL to whichever platform API recognizes
+// its host and returns canonical metadata for it.
+type Service struct {
+	cfg    config.PlatformAPIs
+	client *http.Client
+}
+
+// NewService builds a Service from cfg. A Service with no credentials
+// configured at all is still safe to use: Enrich just returns
+// ErrUnsupported for every URL.
+func NewService(cfg config.PlatformAPIs) *Service {
+	return &Service{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Enrich fetches canonical metadata for sourceURL from whichever official
+// platform API recognizes its host, returning ErrUnsupported if none do
+// (or the matching platform's credentials aren't configured).
+func (s *Service) Enrich(ctx context.Context, sourceURL string) (*entity.Metadata, error) {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("enrich: parse source url: %w", err)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	switch {
+	case host == "youtube.com" || host == "m.youtube.com" || host == "youtu.be":
+		return s.enrichYouTube(ctx, u)
+	case host == "reddit.com" || strings.HasSuffix(host, ".reddit.com"):
+		return s.enrichReddit(ctx, u)
+	default:
+		return nil, ErrUnsupported
+	}
+}
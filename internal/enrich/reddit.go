@@ -0,0 +1,94 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// redditUserAgent identifies daunrodo to Reddit's API, as its API
+// guidelines require every client to send a descriptive, unique
+// User-Agent.
+const redditUserAgent = "daunrodo/1.0 (metadata enrichment)"
+
+// enrichReddit looks up u's submission via Reddit's API, which reports
+// its exact creation timestamp and subreddit, unlike yt-dlp's info JSON
+// (upload_date is day-precision, no category equivalent at all).
+func (s *Service) enrichReddit(ctx context.Context, u *url.URL) (*entity.Metadata, error) {
+	if s.cfg.RedditClientID == "" || s.cfg.RedditClientSecret == "" {
+		return nil, fmt.Errorf("%w: no Reddit API credentials configured", ErrUnsupported)
+	}
+
+	token, err := s.redditToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("enrich: reddit auth: %w", err)
+	}
+
+	endpoint := "https://oauth.reddit.com/api/info?url=" + url.QueryEscape(u.String())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", redditUserAgent)
+
+	var result struct {
+		Data struct {
+			Children []struct {
+				Data struct {
+					CreatedUTC float64 `json:"created_utc"`
+					Subreddit  string  `json:"subreddit"`
+				} `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+
+	if err := s.doJSON(req, &result); err != nil {
+		return nil, fmt.Errorf("enrich: reddit api: %w", err)
+	}
+
+	if len(result.Data.Children) == 0 {
+		return nil, fmt.Errorf("enrich: reddit api: no submission found for %s", u)
+	}
+
+	post := result.Data.Children[0].Data
+	publishedAt := time.Unix(int64(post.CreatedUTC), 0).UTC()
+
+	return &entity.Metadata{
+		Source:      "reddit_api",
+		Category:    post.Subreddit,
+		PublishedAt: &publishedAt,
+	}, nil
+}
+
+// redditToken obtains a short-lived OAuth token via Reddit's
+// client-credentials grant, Reddit's recommended flow for read-only
+// script apps that act on their own behalf rather than a user's.
+func (s *Service) redditToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://www.reddit.com/api/v1/access_token", strings.NewReader("grant_type=client_credentials"))
+	if err != nil {
+		return "", err
+	}
+
+	req.SetBasicAuth(s.cfg.RedditClientID, s.cfg.RedditClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", redditUserAgent)
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := s.doJSON(req, &token); err != nil {
+		return "", err
+	}
+
+	return token.AccessToken, nil
+}
@@ -0,0 +1,72 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// enrichYouTube looks up u's video via the YouTube Data API v3, which
+// reports its exact publish timestamp and license in full, unlike
+// yt-dlp's info JSON (upload_date is day-precision, license is a free-text
+// guess).
+func (s *Service) enrichYouTube(ctx context.Context, u *url.URL) (*entity.Metadata, error) {
+	if s.cfg.YouTubeAPIKey == "" {
+		return nil, fmt.Errorf("%w: no YouTube API key configured", ErrUnsupported)
+	}
+
+	id := youtubeVideoID(u)
+	if id == "" {
+		return nil, fmt.Errorf("enrich: could not extract video id from %s", u)
+	}
+
+	endpoint := "https://www.googleapis.com/youtube/v3/videos?part=snippet,status&id=" +
+		url.QueryEscape(id) + "&key=" + url.QueryEscape(s.cfg.YouTubeAPIKey)
+
+	var result struct {
+		Items []struct {
+			Snippet struct {
+				PublishedAt string `json:"publishedAt"`
+				CategoryID  string `json:"categoryId"`
+			} `json:"snippet"`
+			Status struct {
+				License string `json:"license"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+
+	if err := s.getJSON(ctx, endpoint, &result); err != nil {
+		return nil, fmt.Errorf("enrich: youtube api: %w", err)
+	}
+
+	if len(result.Items) == 0 {
+		return nil, fmt.Errorf("enrich: youtube api: video %s not found", id)
+	}
+
+	item := result.Items[0]
+	md := &entity.Metadata{
+		Source:   "youtube_api",
+		Category: item.Snippet.CategoryID,
+		License:  item.Status.License,
+	}
+
+	if t, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt); err == nil {
+		md.PublishedAt = &t
+	}
+
+	return md, nil
+}
+
+// youtubeVideoID extracts the 11-character video id from either a
+// youtube.com/watch?v=... or a youtu.be/... URL.
+func youtubeVideoID(u *url.URL) string {
+	if strings.Contains(u.Hostname(), "youtu.be") {
+		return strings.Trim(u.Path, "/")
+	}
+
+	return u.Query().Get("v")
+}
@@ -0,0 +1,32 @@
+package entity
+
+// Scope names one bucket of API routes an APIKey can be granted access to;
+// see config.Auth.KeysFile and the route groups in v1.Handler.Register.
+const (
+	ScopeEnqueue = "enqueue"
+	ScopeRead    = "read"
+	ScopeAdmin   = "admin"
+	ScopeFiles   = "files"
+)
+
+// APIKey is one entry loaded from the auth keys file: a caller-presented
+// secret (see middleware.ClientKeyHeader) and the scopes it grants. Label
+// is a human-readable name for the holder, surfaced in audit contexts
+// instead of the raw key.
+type APIKey struct {
+	Key    string   `json:"key"`
+	Scopes []string `json:"scopes"`
+	Label  string   `json:"label,omitempty"`
+}
+
+// HasScope reports whether k grants scope. ScopeAdmin implies every other
+// scope, so an admin key doesn't need to also list them individually.
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArtifactType identifies the kind of derived file an artifact holds.
+type ArtifactType string
+
+const (
+	ArtifactTypeGIF    ArtifactType = "gif"
+	ArtifactTypeWebP   ArtifactType = "webp"
+	ArtifactTypeAVIF   ArtifactType = "avif"
+	// ArtifactTypeSprite is a tiled preview strip of thumbnails at regular
+	// intervals, used to power scrub previews in the web UI.
+	ArtifactTypeSprite ArtifactType = "sprite"
+	// ArtifactTypeComments is a JSON dump of a publication's comments.
+	ArtifactTypeComments ArtifactType = "comments"
+	// ArtifactTypeTranscript is a generated subtitle/transcript file
+	// (SRT/VTT/plain text).
+	ArtifactTypeTranscript ArtifactType = "transcript"
+)
+
+// Artifact is a file derived from a publication's original download, e.g. a
+// GIF conversion of a short clip.
+type Artifact struct {
+	UUID      uuid.UUID
+	Type      ArtifactType
+	FilePath  string
+	// Language is a BCP-47 tag for language-tagged artifacts like
+	// transcripts and translated subtitles; empty when not applicable.
+	Language  string
+	CreatedAt time.Time
+}
+
+// NewArtifact builds an Artifact of the given type pointing at filePath.
+func NewArtifact(t ArtifactType, filePath string) Artifact {
+	return Artifact{UUID: uuid.New(), Type: t, FilePath: filePath, CreatedAt: time.Now()}
+}
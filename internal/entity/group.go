@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobGroup ties together jobs that were created from the same batch, e.g. a
+// watch-folder import, a playlist expansion or an account archive.
+type JobGroup struct {
+	ID     uuid.UUID
+	JobIDs []uuid.UUID
+	Source string
+	Preset string
+	// Items maps a discovered item's canonical media ID (falling back to its
+	// URL when the extractor doesn't provide one) to the job created for
+	// it, so an archive can be resumed or synced and pick up only missing
+	// items.
+	Items map[string]uuid.UUID
+	// Total is the number of items discovered for this group, so callers
+	// can compute completion percentage as len(JobIDs)/Total. It is 0 when
+	// unknown (e.g. watch-folder imports).
+	Total     int
+	CreatedAt time.Time
+	// GenerateM3U marks this group as a music album/playlist expansion
+	// (see JobService.enqueueAlbum) whose tracks should be collected into
+	// an m3u playlist once every job in JobIDs reaches a terminal status;
+	// see PlaylistPath.
+	GenerateM3U bool
+	// PlaylistPath is the generated m3u playlist's path, set once
+	// GenerateM3U's condition is met; empty until then.
+	PlaylistPath string
+	// Status summarizes how every child job in JobIDs finished, set once
+	// the last of them reaches a terminal status; see
+	// YtDlpRunner.checkGroupComplete. Empty until then.
+	Status GroupStatus
+	// Failures records the items that failed, populated alongside Status
+	// when it's GroupStatusPartial or GroupStatusError.
+	Failures []GroupItemError
+	// Truncated reports whether this group's source listed at least as many
+	// items as JobService.effectiveMaxItems allowed, so Total reflects the
+	// enforced cap rather than everything the source actually had; see
+	// JobService.EnqueuePlaylist, enqueueAlbum and enqueueRedditGallery.
+	Truncated bool
+}
+
+// GroupStatus summarizes how a JobGroup's child jobs collectively
+// finished; see JobGroup.Status.
+type GroupStatus string
+
+const (
+	// GroupStatusDone means every child job finished successfully.
+	GroupStatusDone GroupStatus = "done"
+	// GroupStatusPartial means some child jobs finished successfully and
+	// others failed, e.g. deleted gallery items or geo-blocked playlist
+	// entries, without failing the jobs that did succeed.
+	GroupStatusPartial GroupStatus = "finished_partial"
+	// GroupStatusError means every child job failed.
+	GroupStatusError GroupStatus = "error"
+)
+
+// GroupItemError records one failed child job, so a caller can see which
+// specific items failed and why without looking up every child job
+// individually; see JobGroup.Failures.
+type GroupItemError struct {
+	JobID uuid.UUID
+	URL   string
+	Error string
+}
+
+// Progress returns the fraction of discovered items that have a job, or 0
+// when Total is unknown.
+func (g *JobGroup) Progress() float64 {
+	if g.Total == 0 {
+		return 0
+	}
+
+	return float64(len(g.JobIDs)) / float64(g.Total)
+}
+
+// NewJobGroup builds an empty group for the given source description (a
+// file path, URL, etc).
+func NewJobGroup(source, preset string) *JobGroup {
+	return &JobGroup{
+		ID:        uuid.New(),
+		Source:    source,
+		Preset:    preset,
+		Items:     make(map[string]uuid.UUID),
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,456 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus describes the lifecycle state of a download job.
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusQueued    JobStatus = "queued"
+	// JobStatusScheduled means the job is held back from the worker pool
+	// until config.Job.ActiveHours's next window opens; see
+	// JobService.SetActiveHours and Job.ScheduledFor.
+	JobStatusScheduled JobStatus = "scheduled"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusPaused    JobStatus = "paused"
+	JobStatusDone      JobStatus = "done"
+	JobStatusError     JobStatus = "error"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Job represents a single download request tracked by the service.
+//
+// A job is handed across goroutines throughout its life: the worker pool
+// reads it while the runner's goroutine mutates it, Cancel/Pause/Resume
+// mutate it from an HTTP handler's goroutine concurrently with the runner,
+// and getJob/listJobs json.Encode it while either may still be writing. mu
+// guards every field that changes after the job starts running and is read
+// across that boundary (status, events, log, error, runs, items); it's a
+// pointer so a copy of Job (e.g. for MarshalJSON) doesn't copy lock state.
+// Every other field is either set once before the job is queued or is only
+// ever touched by the runner goroutine that owns the job for its run.
+type Job struct {
+	mu *sync.Mutex
+
+	ID     uuid.UUID
+	URL    string
+	Preset string
+	status JobStatus
+	error  string
+	// Attempt counts how many times the runner has tried this job, starting
+	// at 1 for the first try; see the retry policy in config.Job.
+	Attempt   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// StartedAt is when the runner picked the job up and began downloading,
+	// nil until then. Used to measure actual run duration for
+	// JobService's per-domain wait-time estimates.
+	StartedAt *time.Time
+	// RefetchOf links a job spawned to re-download an expired publication
+	// back to the original publication record.
+	RefetchOf *uuid.UUID
+	events    []JobEvent
+	// ClipStart/ClipEnd request a time range of the source to download
+	// instead of the whole media, mapped to yt-dlp's --download-sections.
+	// Nil means unbounded on that side.
+	ClipStart *time.Duration
+	ClipEnd   *time.Duration
+	// Profile names the cookie/account profile used for this job, if any.
+	// Jobs sharing a profile are serialized by the worker pool's
+	// per-profile limiter to avoid triggering platform anti-abuse systems.
+	Profile string
+	// GifOutput requests an additional GIF/webp/avif artifact be produced
+	// alongside the original download.
+	GifOutput *GifOptions
+	// ImageTransform requests post-processing (resize/convert/EXIF strip)
+	// of gallery-dl image results.
+	ImageTransform *ImageTransform
+	// Comments opts into collecting the source's comments alongside the
+	// media, stored as an ArtifactTypeComments artifact.
+	Comments      *CommentsOptions
+	Transcription *TranscriptionOptions
+	Translation   *TranslationOptions
+	// Webhook overrides the server-wide default webhook URL for this job's
+	// status notifications, if set.
+	Webhook string
+	// FaultMode selects a simulated downloader failure mode ("slow",
+	// "flaky", "partial", "huge_stdout") for end-to-end resilience testing.
+	// Only honored when config.Job.FaultInjectionEnabled is set; see
+	// FaultInjector.
+	FaultMode string
+	// DownloadArchive, when set, is passed to yt-dlp as
+	// --download-archive so items already recorded there are skipped
+	// instead of re-downloaded. Set on the child jobs of
+	// JobService.EnqueuePlaylist: from its caller (SchedulerService, for
+	// a recurring Schedule) or, when that is empty and a playlist archive
+	// directory is configured, from a file derived from the playlist URL.
+	DownloadArchive string
+	// Log holds the downloader's captured stdout/stderr, size-capped and
+	// redacted, across every retry attempt; see GET /v1/jobs/{id}/logs.
+	log string
+	// RequestID correlates this job back to the API call that created it
+	// (see middleware.RequestID), and is attached to worker logs and
+	// external process audit entries for end-to-end tracing.
+	RequestID string
+	// Labels are free-form key/value tags set at enqueue time, e.g. to
+	// group downloads by project or client. JobRepository indexes jobs by
+	// label so they can be listed by a "key:value" filter; see
+	// JobRepository.ListByLabel.
+	Labels map[string]string
+	// ScheduledFor is set alongside JobStatusScheduled to the next time
+	// config.Job.ActiveHours's window opens, nil otherwise.
+	ScheduledFor *time.Time
+	// MaxRateKbps caps this job's own download bandwidth in kilobits/sec,
+	// overriding config.Job.MaxRateKbps when lower than it; 0 defers to the
+	// server-wide cap entirely.
+	MaxRateKbps int
+	// runs records one entry per downloader execution attempt, for
+	// debugging why a specific retry failed without grepping Log; see GET
+	// /v1/jobs/{id}/runs.
+	runs []JobRun
+	// Submitter identifies who enqueued this job (an API key or source IP,
+	// whichever the caller presented; see middleware.ClientIdentity), used
+	// by the worker pool to schedule round-robin across submitters instead
+	// of plain FIFO, so one client's bulk submission doesn't starve
+	// everyone else's jobs behind it. Empty is its own bucket, sharing
+	// fairness with every other unidentified caller.
+	Submitter string
+	// items breaks down progress for a job whose single downloader process
+	// produces more than one file, e.g. a gallery-dl post with several
+	// images, attributed as the runner's progress handler parses each
+	// item's own progress out of the process output; see
+	// YtDlpRunner.buildItemTracker. A playlist/channel URL enqueued via
+	// JobService.EnqueuePlaylist instead gets one child Job per item under
+	// an entity.JobGroup, so it has no need for Items; see
+	// JobGroup.Progress.
+	items []JobItem
+	// GroupID links this job back to the entity.JobGroup it was created as
+	// a child of (e.g. a playlist item, gallery image or album track), nil
+	// for a job enqueued directly. See JobGroup.
+	GroupID *uuid.UUID
+	// TrackNumber and Album tag this job as one track of a music
+	// album/playlist expansion (see JobService.enqueueAlbum): TrackNumber
+	// is embedded into the downloaded file's metadata and output filename,
+	// Album into its metadata, both via the runner's ffmpeg postprocessor
+	// args. 0/empty for a job that isn't part of an album.
+	TrackNumber int
+	Album       string
+	// OutputPath is the absolute path of the file YtDlpRunner.Run produced
+	// for a track job or a config.Job.DirectoryLayouts job (both get -o
+	// names that start with the job ID), set once the download finishes
+	// successfully; used to build the album's m3u playlist. Empty for any
+	// other job, since its output filename isn't predictable enough to
+	// locate this way; see GET /v1/jobs/{id}/publication for those instead.
+	OutputPath string
+}
+
+// JobItem is one file within a multi-file Job; see Job.Items.
+type JobItem struct {
+	// Index is the item's 1-based position as reported by the downloader.
+	Index int
+	Title string
+	// Progress is a percentage in [0, 100].
+	Progress int
+	Status   JobStatus
+	// TotalBytes is the item's size as last reported by the downloader's
+	// own progress line (e.g. "45.2% of 10.00MiB"), 0 until known. It only
+	// ever grows, never shrinks, so a later, more precise estimate can't
+	// make Job.OverallProgress regress.
+	TotalBytes int64
+	// DownloadedBytes is TotalBytes*Progress/100 as of the last update,
+	// kept rather than recomputed on read so Job.OverallProgress stays
+	// monotonic even while a later item's TotalBytes is still unknown.
+	DownloadedBytes int64
+}
+
+// UpsertItem records progress for the item at index, adding it to Items if
+// not already present. title is left unchanged on an existing item when
+// given empty, since not every progress line repeats it. totalBytes is the
+// item's size if the downloader reported one on this line, 0 otherwise; see
+// JobItem.TotalBytes.
+func (j *Job) UpsertItem(index int, title string, progress int, status JobStatus, totalBytes int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.items {
+		if j.items[i].Index == index {
+			if title != "" {
+				j.items[i].Title = title
+			}
+
+			j.items[i].Progress = progress
+			j.items[i].Status = status
+
+			if totalBytes > j.items[i].TotalBytes {
+				j.items[i].TotalBytes = totalBytes
+			}
+
+			j.items[i].DownloadedBytes = j.items[i].TotalBytes * int64(progress) / 100
+
+			return
+		}
+	}
+
+	item := JobItem{Index: index, Title: title, Progress: progress, Status: status, TotalBytes: totalBytes}
+	item.DownloadedBytes = item.TotalBytes * int64(progress) / 100
+	j.items = append(j.items, item)
+}
+
+// ItemsSnapshot returns a copy of the job's per-item progress breakdown as
+// of now, safe to range over or json.Encode from a different goroutine than
+// whichever is calling UpsertItem; see Job's doc comment.
+func (j *Job) ItemsSnapshot() []JobItem {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]JobItem(nil), j.items...)
+}
+
+// OverallProgress returns the job's overall completion percentage across
+// every item, computed from cumulative bytes downloaded vs cumulative
+// total bytes where the downloader has reported sizes, so an item whose
+// size is still unknown adds nothing to either side. Sizes come only from
+// the downloader's progress lines, not from a probe, so the result can
+// still drop once a later item first reports its size and that size joins
+// the total. It falls back to averaging each item's own percentage when no
+// item has a known size yet. Returns 0 for a job with no items.
+func (j *Job) OverallProgress() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(j.items) == 0 {
+		return 0
+	}
+
+	var downloaded, total int64
+
+	for _, item := range j.items {
+		downloaded += item.DownloadedBytes
+		total += item.TotalBytes
+	}
+
+	if total > 0 {
+		return int(downloaded * 100 / total)
+	}
+
+	var sum int
+	for _, item := range j.items {
+		sum += item.Progress
+	}
+
+	return sum / len(j.items)
+}
+
+// JobRun records a single downloader execution attempt.
+type JobRun struct {
+	Attempt         int
+	StartedAt       time.Time
+	FinishedAt      time.Time
+	ExitCode        int
+	StderrTail      string
+	Proxy           string
+	BytesDownloaded int64
+}
+
+// AddRun appends a completed execution attempt to the job's run history.
+func (j *Job) AddRun(run JobRun) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.runs = append(j.runs, run)
+}
+
+// RunsSnapshot returns a copy of the job's run history as of now, safe to
+// range over or json.Encode from a different goroutine than whichever is
+// calling AddRun; see Job's doc comment.
+func (j *Job) RunsSnapshot() []JobRun {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]JobRun(nil), j.runs...)
+}
+
+// CommentsOptions bounds the opt-in comment collection.
+type CommentsOptions struct {
+	MaxCount int
+}
+
+// TranscriptionOptions opts a job into post-download transcription via
+// whisper.cpp. It is processed by a separate queue so it doesn't block
+// download workers.
+type TranscriptionOptions struct {
+	// Model names a whisper.cpp model size (e.g. "base", "small"); empty
+	// uses the server default.
+	Model string
+}
+
+// TranslationOptions requests translated copies of a transcript/subtitle
+// artifact into the given target languages (BCP-47 tags).
+type TranslationOptions struct {
+	TargetLangs []string
+}
+
+// ImageTransform configures the optional image post-processing pipeline
+// applied to gallery-dl results.
+type ImageTransform struct {
+	MaxDimension int
+	Format       string // e.g. "webp", "jpeg"; empty keeps the original format
+	Quality      int
+	StripEXIF    bool
+}
+
+// GifOptions controls the short-clip-to-animated-image conversion an
+// extractor step performs alongside the normal download.
+type GifOptions struct {
+	Format   ArtifactType // gif, webp or avif
+	MaxWidth int
+	FPS      int
+}
+
+// JobEvent records a single notable thing that happened to a job, e.g. a
+// status transition or an automatic decision like preset auto-selection.
+type JobEvent struct {
+	Time    time.Time
+	Type    string
+	Message string
+}
+
+// AddEvent appends a timestamped event to the job's history.
+func (j *Job) AddEvent(eventType, message string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.events = append(j.events, JobEvent{Time: time.Now(), Type: eventType, Message: message})
+}
+
+// EventsSnapshot returns a copy of the job's event history as of now, safe
+// to range over or json.Encode from a different goroutine than whichever
+// is calling AddEvent; see Job's doc comment.
+func (j *Job) EventsSnapshot() []JobEvent {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]JobEvent(nil), j.events...)
+}
+
+// Status returns the job's current lifecycle status; see SetStatus.
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.status
+}
+
+// SetStatus transitions the job to status s.
+func (j *Job) SetStatus(s JobStatus) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status = s
+}
+
+// AppendLog appends entry to the job's captured downloader output,
+// enforcing the same maxJobLogBytes cap as the unexported package-level
+// appendJobLog helper that calls it; see YtDlpRunner.Run.
+func (j *Job) AppendLog(entry string, maxBytes int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.log += entry
+	if len(j.log) > maxBytes {
+		j.log = "... [truncated]\n" + j.log[len(j.log)-maxBytes:]
+	}
+}
+
+// LogSnapshot returns the job's captured downloader output as of now; see
+// AppendLog.
+func (j *Job) LogSnapshot() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.log
+}
+
+// Error returns the job's last recorded failure message, empty if it
+// hasn't failed; see SetError.
+func (j *Job) Error() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.error
+}
+
+// SetError records msg as the job's last failure message.
+func (j *Job) SetError(msg string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.error = msg
+}
+
+// jobJSON mirrors Job's exported shape for MarshalJSON, substituting
+// mutex-guarded snapshots for the fields mu protects. Field order doesn't
+// matter for JSON, and the key names below match what direct struct
+// marshaling produced before those fields were guarded, so this is not a
+// wire-format change.
+type jobJSON struct {
+	*jobAlias
+	Status JobStatus  `json:"Status"`
+	Events []JobEvent `json:"Events"`
+	Log    string     `json:"Log"`
+	Error  string     `json:"Error"`
+	Runs   []JobRun   `json:"Runs"`
+	Items  []JobItem  `json:"Items"`
+}
+
+// jobAlias has the same fields as Job minus mu, letting MarshalJSON reuse
+// the default struct marshaling for everything mu doesn't protect without
+// recursing back into Job.MarshalJSON.
+type jobAlias Job
+
+func (j *Job) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jobJSON{
+		jobAlias: (*jobAlias)(j),
+		Status:   j.Status(),
+		Events:   j.EventsSnapshot(),
+		Log:      j.LogSnapshot(),
+		Error:    j.Error(),
+		Runs:     j.RunsSnapshot(),
+		Items:    j.ItemsSnapshot(),
+	})
+}
+
+// NewJob builds a pending job for the given source URL and preset. Job.ID
+// is always a random, unguessable v4 UUID: it never encodes the URL or
+// preset, so two users requesting the same source can't detect each other
+// by comparing IDs. Callers that want to detect duplicate requests for the
+// same URL+preset should look them up by DedupKey instead; see
+// JobRepository.GetByDedupKey and JobService.EnqueueOptions.Dedupe.
+func NewJob(url, preset string) *Job {
+	now := time.Now()
+
+	return &Job{
+		mu:        &sync.Mutex{},
+		ID:        uuid.New(),
+		URL:       url,
+		Preset:    preset,
+		status:    JobStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// DedupKey returns the lookup key JobRepository.GetByDedupKey indexes jobs
+// by: the same URL and preset, compared case-insensitively, always map to
+// the same key regardless of the (randomly generated) job ID.
+func DedupKey(url, preset string) string {
+	return strings.ToLower(url) + "|" + strings.ToLower(preset)
+}
@@ -0,0 +1,16 @@
+package entity
+
+// KeyDefaults holds the default enqueue options an API key has configured
+// for itself, via PUT /v1/me/defaults, so a minimal client (e.g. a phone
+// shortcut or webhook source) can enqueue with nothing but a URL and
+// inherit them instead of repeating the same options on every request.
+type KeyDefaults struct {
+	// Key is the API key these defaults belong to (see
+	// middleware.ClientKeyHeader); it is the lookup key itself, not a
+	// surrogate ID, since a key already uniquely identifies its owner.
+	Key     string
+	Preset  string
+	Profile string
+	Webhook string
+	Labels  map[string]string
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// Chapter is a named segment of a video, as reported by the extractor.
+type Chapter struct {
+	Title string
+	Start time.Duration
+	End   time.Duration
+}
+
+// Metadata holds the subset of an extractor's info JSON that daunrodo
+// models directly, so it can be served without re-parsing the raw info
+// JSON on every request.
+type Metadata struct {
+	Description string
+	Tags        []string
+	UploadDate  string
+	Chapters    []Chapter
+	// Source identifies where PublishedAt/License/Category came from: empty
+	// when Metadata only holds extractor-parsed fields, or a platform API
+	// name (e.g. "youtube_api", "reddit_api") once an official platform API
+	// call has filled them in with canonical values the extractor's own
+	// info JSON doesn't reliably report. See service.PublicationService.
+	// EnrichMetadata.
+	Source      string
+	PublishedAt *time.Time
+	License     string
+	Category    string
+}
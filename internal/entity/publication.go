@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PublicationStatus describes the lifecycle state of a downloaded file record.
+type PublicationStatus string
+
+const (
+	// PublicationStatusReady means the file is present on disk and servable.
+	PublicationStatusReady PublicationStatus = "ready"
+	// PublicationStatusExpired means the metadata is retained but the
+	// underlying file has been removed by the retention sweep.
+	PublicationStatusExpired PublicationStatus = "expired"
+)
+
+// Publication is the durable record of a job's output: the metadata survives
+// for longer than the file itself, see internal/service retention sweep.
+type Publication struct {
+	UUID      uuid.UUID
+	JobID     uuid.UUID
+	SourceURL string
+	Preset    string
+	FilePath  string
+	// FileSHA256 is the hex-encoded content hash of the file at FilePath,
+	// computed lazily the first time it's needed (see
+	// PublicationService.EnsureFileHash) and used as the content-addressed
+	// path under /v1/files/{sha256}.
+	FileSHA256 string
+	Title      string
+	Author     string
+	// FileSizeBytes and Resolution and Duration describe the downloaded
+	// file; Resolution is empty for audio-only/image downloads.
+	FileSizeBytes int64
+	Resolution    string
+	Duration      time.Duration
+	Status        PublicationStatus
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+	// RefetchOf links a publication created by re-downloading an expired one
+	// back to the original record, see PublicationService.Refetch.
+	RefetchOf *uuid.UUID
+	// Artifacts holds files derived from the original download, e.g. a GIF
+	// conversion of a short clip.
+	Artifacts []Artifact
+	// Metadata holds chapters/description/tags parsed from the extractor's
+	// info JSON; nil when the extractor didn't report any.
+	Metadata *Metadata
+	// InfoJSONPath points at the gzip-compressed raw info JSON produced by
+	// yt-dlp/gallery-dl, kept for power users and downstream tooling that
+	// need fields daunrodo doesn't model; empty when not retained.
+	InfoJSONPath string
+	// ThumbnailPath points at a single cover-frame image for the
+	// publication: either the thumbnail yt-dlp wrote alongside the
+	// download, or one generated on first request into
+	// config.Thumbnail.CacheDir; empty until either happens. See
+	// PublicationService.EnsureThumbnail.
+	ThumbnailPath string
+	// DownloadCount is how many times the file has been served, and
+	// LastAccessedAt when that last happened; see
+	// PublicationService.RecordAccess. LastAccessedAt is nil if the file has
+	// never been downloaded, which the retention sweep can use to evict
+	// never-touched files first.
+	DownloadCount  int
+	LastAccessedAt *time.Time
+}
+
+// AddArtifact appends a derived artifact to the publication.
+func (p *Publication) AddArtifact(a Artifact) {
+	p.Artifacts = append(p.Artifacts, a)
+}
+
+// ArtifactByType returns the first artifact of the given type, if any.
+func (p *Publication) ArtifactByType(t ArtifactType) (Artifact, bool) {
+	for _, a := range p.Artifacts {
+		if a.Type == t {
+			return a, true
+		}
+	}
+
+	return Artifact{}, false
+}
+
+// NewPublication builds a ready publication record for a finished job.
+func NewPublication(jobID uuid.UUID, sourceURL, preset, filePath, title string) *Publication {
+	return &Publication{
+		UUID:      uuid.New(),
+		JobID:     jobID,
+		SourceURL: sourceURL,
+		Preset:    preset,
+		FilePath:  filePath,
+		Title:     title,
+		Status:    PublicationStatusReady,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Expire marks the publication as expired, dropping the file path since the
+// underlying file is no longer present on disk.
+func (p *Publication) Expire() {
+	p.Status = PublicationStatusExpired
+	p.FilePath = ""
+}
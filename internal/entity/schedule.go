@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schedule registers a source URL to be periodically re-probed and
+// expanded into child jobs, e.g. "check this channel every day for new
+// uploads". See service.SchedulerService.
+type Schedule struct {
+	ID     uuid.UUID
+	URL    string
+	Preset string
+	// Cron is a standard 5-field cron expression, see pkg/cron.
+	Cron string
+	// Enabled gates whether the scheduler loop fires this schedule; a
+	// disabled schedule is kept around (not deleted) so its history and
+	// download archive survive a pause.
+	Enabled bool
+	// DownloadArchive is the path to the yt-dlp download-archive file this
+	// schedule's runs are deduped against, so re-running only fetches items
+	// not already downloaded. See entity.Job.DownloadArchive.
+	DownloadArchive string
+	LastRunAt       time.Time
+	CreatedAt       time.Time
+}
+
+// NewSchedule builds an enabled schedule for url/preset firing on cron.
+// archiveFile should be a path unique to this schedule.
+func NewSchedule(url, preset, cron, archiveFile string) *Schedule {
+	return &Schedule{
+		ID:              uuid.New(),
+		URL:             url,
+		Preset:          preset,
+		Cron:            cron,
+		Enabled:         true,
+		DownloadArchive: archiveFile,
+		CreatedAt:       time.Now(),
+	}
+}
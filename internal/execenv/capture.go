@@ -0,0 +1,63 @@
+package execenv
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// CappedWriter collects up to max bytes written to it, silently dropping
+// anything beyond that so a runaway/huge_stdout downloader can't grow a
+// job's captured log without bound. See OutputCapture.
+type CappedWriter struct {
+	max       int
+	buf       bytes.Buffer
+	truncated bool
+}
+
+// NewCappedWriter builds a CappedWriter that retains at most max bytes.
+func NewCappedWriter(max int) *CappedWriter {
+	return &CappedWriter{max: max}
+}
+
+func (w *CappedWriter) Write(p []byte) (int, error) {
+	if room := w.max - w.buf.Len(); room > 0 {
+		if len(p) > room {
+			w.buf.Write(p[:room])
+			w.truncated = true
+		} else {
+			w.buf.Write(p)
+		}
+	} else if len(p) > 0 {
+		w.truncated = true
+	}
+
+	return len(p), nil
+}
+
+// String returns what was captured, with a truncation notice appended if
+// the cap was hit.
+func (w *CappedWriter) String() string {
+	if !w.truncated {
+		return w.buf.String()
+	}
+
+	return w.buf.String() + "\n... [truncated]"
+}
+
+// secretPatterns matches common "key=value"/"key: value" secret shapes
+// (passwords, tokens, cookies, auth headers) that a downloader might echo
+// to stdout/stderr, e.g. while logging the request it made.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(password|passwd|token|secret|api[_-]?key|cookie)\s*[=:]\s*\S+`),
+	regexp.MustCompile(`(?i)(authorization:\s*(bearer|basic)\s+)\S+`),
+}
+
+// RedactOutput blanks out secret-shaped substrings in captured downloader
+// output before it's stored on the job or served over the API.
+func RedactOutput(s string) string {
+	for _, re := range secretPatterns {
+		s = re.ReplaceAllString(s, "$1[REDACTED]")
+	}
+
+	return s
+}
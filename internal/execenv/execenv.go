@@ -0,0 +1,75 @@
+// Package execenv builds exec.Cmd instances for the external tools daunrodo
+// shells out to (yt-dlp, gallery-dl, ffmpeg, whisper.cpp, ...) with a
+// minimal, job-scoped environment: cwd set to the job's own temp/output
+// directory, HOME/TMPDIR pointed there too so caches and config files the
+// tool writes land somewhere that gets cleaned up with the job, and no
+// secrets inherited from this process's environment beyond a small
+// allowlist.
+package execenv
+
+import (
+	"context"
+	"os"
+	"os/exec"
+
+	"github.com/evan-sm/daunrodo/pkg/shellquote"
+)
+
+// allowlist are the variables passed through from this process's own
+// environment; everything else (API keys, tokens, ...) is dropped so a
+// compromised or malicious extractor script can't read them.
+var allowlist = []string{"PATH", "LANG"}
+
+// Command builds a Cmd for name scoped to dir: cwd is dir, HOME and TMPDIR
+// point at dir, and the environment is built from allowlist rather than
+// inherited wholesale.
+func Command(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+
+	env := []string{"HOME=" + dir, "TMPDIR=" + dir}
+	for _, k := range allowlist {
+		if v, ok := os.LookupEnv(k); ok {
+			env = append(env, k+"="+v)
+		}
+	}
+
+	cmd.Env = env
+
+	return cmd
+}
+
+// sensitiveFlags lists flags whose value is redacted by CommandLine, e.g.
+// credentials passed to yt-dlp/gallery-dl.
+var sensitiveFlags = map[string]bool{
+	"-u": true, "--username": true,
+	"-p": true, "--password": true,
+	"--proxy": true,
+	"--cookies": true,
+	"--video-password": true,
+	"--ap-password": true,
+}
+
+// CommandLine renders name+args as a reproducible shell command line with
+// sensitive flag values redacted, for recording in a job's event log (see
+// the "command" event type and GET /v1/jobs/{id}/commands).
+func CommandLine(name string, args []string) string {
+	redacted := make([]string, 0, len(args)+1)
+	redacted = append(redacted, name)
+
+	redactNext := false
+
+	for _, a := range args {
+		if redactNext {
+			redacted = append(redacted, "[REDACTED]")
+			redactNext = false
+
+			continue
+		}
+
+		redacted = append(redacted, a)
+		redactNext = sensitiveFlags[a]
+	}
+
+	return shellquote.Join(redacted)
+}
@@ -0,0 +1,89 @@
+// Package httpserver wraps net/http.Server with the timeouts and shutdown
+// behaviour the rest of the codebase expects.
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultReadTimeout       = 5 * time.Second
+	defaultWriteTimeout      = 10 * time.Second
+	defaultIdleTimeout       = 60 * time.Second
+	defaultReadHeaderTimeout = 5 * time.Second
+)
+
+// Options configures the timeouts New sets on the underlying http.Server.
+// A zero Options (the zero value of every field) resolves to the package
+// defaults via withDefaults; set only the fields a caller wants to
+// override. WriteTimeout applies to every route; a handler needing longer
+// (e.g. a large file download) extends its own per-request deadline via
+// middleware.WriteTimeout rather than raising this server-wide.
+type Options struct {
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+}
+
+// withDefaults returns a copy of o with every zero-valued field replaced by
+// its package default.
+func (o Options) withDefaults() Options {
+	if o.ReadTimeout == 0 {
+		o.ReadTimeout = defaultReadTimeout
+	}
+
+	if o.WriteTimeout == 0 {
+		o.WriteTimeout = defaultWriteTimeout
+	}
+
+	if o.IdleTimeout == 0 {
+		o.IdleTimeout = defaultIdleTimeout
+	}
+
+	if o.ReadHeaderTimeout == 0 {
+		o.ReadHeaderTimeout = defaultReadHeaderTimeout
+	}
+
+	return o
+}
+
+// Server is a thin wrapper around http.Server.
+type Server struct {
+	httpServer *http.Server
+}
+
+// New builds a Server listening on addr and serving handler, with timeouts
+// from opts (zero fields fall back to sane defaults; see Options).
+func New(addr string, handler http.Handler, opts Options) *Server {
+	opts = opts.withDefaults()
+
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadTimeout:       opts.ReadTimeout,
+			WriteTimeout:      opts.WriteTimeout,
+			IdleTimeout:       opts.IdleTimeout,
+			ReadHeaderTimeout: opts.ReadHeaderTimeout,
+		},
+	}
+}
+
+// Start begins serving and blocks until the server stops. It returns nil on
+// a clean shutdown.
+func (s *Server) Start() error {
+	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight requests to
+// finish or ctx to expire.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+)
+
+// ConcurrencyLimit rejects a request with 429 if the client already has
+// maxPerClient requests in flight through this middleware, complementing a
+// byte-rate egress cap (see v1.FileServer's Egress fields) by also bounding
+// how many parallel streams a single client can hold open. Clients are
+// identified by ClientIdentity.
+func ConcurrencyLimit(maxPerClient int) func(http.Handler) http.Handler {
+	if maxPerClient <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	tracker := &concurrencyTracker{inFlight: make(map[string]int)}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := ClientIdentity(r)
+
+			if !tracker.acquire(key, maxPerClient) {
+				http.Error(w, "too many concurrent downloads", http.StatusTooManyRequests)
+				return
+			}
+			defer tracker.release(key)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// concurrencyTracker counts in-flight requests per client key.
+type concurrencyTracker struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+}
+
+// acquire reserves a slot for key, reporting false (reserving nothing) if
+// key is already at max.
+func (t *concurrencyTracker) acquire(key string, max int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.inFlight[key] >= max {
+		return false
+	}
+
+	t.inFlight[key]++
+
+	return true
+}
+
+// release frees a slot reserved by acquire, dropping the key entirely once
+// it reaches zero so a one-off client doesn't leak a map entry forever.
+func (t *concurrencyTracker) release(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.inFlight[key]--
+	if t.inFlight[key] <= 0 {
+		delete(t.inFlight, key)
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import "net/http"
+
+// Deprecated wraps next, marking every response with the RFC 8594
+// Deprecation header (and Sunset, if set) so clients of a frozen API
+// version know to migrate before it's retired. sunset is an HTTP-date
+// (e.g. "Wed, 1 Jan 2027 00:00:00 GMT"); leave it empty to omit the header
+// when no retirement date has been decided yet.
+func Deprecated(sunset string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+
+			if sunset != "" {
+				w.Header().Set("Sunset", sunset)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
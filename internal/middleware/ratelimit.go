@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evan-sm/daunrodo/pkg/ratelimit"
+)
+
+// ClientKeyHeader names a client-supplied API key header; when present it
+// identifies the client for rate limiting instead of its IP, so clients
+// behind a shared NAT/proxy aren't limited together. The same header also
+// scopes per-key enqueue defaults; see v1.Handler.getMyDefaults.
+const ClientKeyHeader = "X-Daunrodo-Api-Key"
+
+// ClientIdentity returns the caller's ClientKeyHeader value if set, falling
+// back to the request's remote address (apply RealIP first so that reflects
+// the real client, not a shared proxy). It's the same identity RateLimit
+// buckets by, also used to attribute a job to its submitter for the worker
+// pool's fair-share scheduling; see entity.Job.Submitter.
+func ClientIdentity(r *http.Request) string {
+	if key := r.Header.Get(ClientKeyHeader); key != "" {
+		return key
+	}
+
+	return peerIP(r.RemoteAddr).String()
+}
+
+// RateLimit rejects requests beyond limiter's per-client rate with 429 and a
+// Retry-After header. Clients are identified by ClientIdentity.
+func RateLimit(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, retryAfter := limiter.Allow(ClientIdentity(r))
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds()+1)))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Package middleware holds cross-cutting net/http middleware shared across
+// the delivery layer, e.g. trusted-proxy IP resolution and request logging.
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseTrustedProxies parses CIDR strings (e.g. "10.0.0.0/8") from config
+// into the form RealIP expects, skipping and ignoring malformed entries
+// since a bad config value shouldn't take the server down.
+func ParseTrustedProxies(cidrs []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		if _, n, err := net.ParseCIDR(cidr); err == nil {
+			nets = append(nets, n)
+		}
+	}
+
+	return nets
+}
+
+// RealIP rewrites r.RemoteAddr to the client IP reported by X-Forwarded-For,
+// but only when the immediate peer (r.RemoteAddr) is one of trusted, so an
+// untrusted client can't spoof its own address. With no trusted proxies
+// configured it's a no-op.
+func RealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := peerIP(r.RemoteAddr); ip != nil && isTrusted(ip, trusted) {
+				if fwd := firstForwardedFor(r.Header.Get("X-Forwarded-For")); fwd != "" {
+					r.RemoteAddr = fwd
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func peerIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	return net.ParseIP(host)
+}
+
+func isTrusted(ip net.IP, trusted []*net.IPNet) bool {
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// firstForwardedFor returns the left-most (original client) address from an
+// X-Forwarded-For header value.
+func firstForwardedFor(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.Split(header, ",")[0])
+}
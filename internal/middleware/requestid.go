@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/pkg/logger"
+)
+
+// RequestIDHeader is both the inbound header honored as a caller-supplied
+// request ID and the outbound header it's echoed on, so a request can be
+// correlated across the API, worker logs and external process audit
+// entries end to end.
+const RequestIDHeader = "X-Request-Id"
+
+// RequestID ensures every request carries an ID: the inbound
+// RequestIDHeader if the caller set one, otherwise a freshly generated one.
+// The ID is stored in the request context via logger.WithRequestID, so any
+// logging done with that context (or one derived from it) is automatically
+// tagged with it, and it's echoed back on the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := logger.WithRequestID(r.Context(), id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the request ID stashed by RequestID, or ""
+// if none is present (e.g. in a context not derived from a request that
+// passed through that middleware).
+func RequestIDFromContext(ctx context.Context) string {
+	return logger.RequestIDFromContext(ctx)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// WriteTimeout extends next's response write deadline to d, overriding the
+// server-wide http.Server.WriteTimeout (see httpserver.Options) for routes
+// that legitimately need longer, e.g. a large file download a slow client
+// is still pulling from past the default deadline. A no-op if the
+// underlying connection doesn't support per-request deadlines (it always
+// does for the net/http server this project runs).
+func WriteTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
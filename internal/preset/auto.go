@@ -0,0 +1,44 @@
+package preset
+
+import (
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/crawler"
+)
+
+// AutoRule picks a preset for items matching Type and, for video, at most
+// MaxDuration (zero means unbounded). Rules are evaluated in order; the
+// first match wins.
+type AutoRule struct {
+	Type        crawler.MediaType
+	MaxDuration time.Duration
+	Preset      string
+}
+
+// DefaultAutoRules mirrors the project's default auto-selection policy:
+// images go out untouched, audio-only sources get the audio preset, short
+// videos get the higher quality preset and longer ones a lighter one.
+func DefaultAutoRules() []AutoRule {
+	return []AutoRule{
+		{Type: crawler.MediaTypeImage, Preset: "original"},
+		{Type: crawler.MediaTypeAudio, Preset: "audio"},
+		{Type: crawler.MediaTypeVideo, MaxDuration: 10 * time.Minute, Preset: "mp4-1080"},
+		{Type: crawler.MediaTypeVideo, Preset: "mp4-720"},
+	}
+}
+
+// SelectAuto resolves the "auto" preset for item against rules, returning
+// the first matching rule's preset name, or "original" if none matches.
+func SelectAuto(item crawler.Item, rules []AutoRule) string {
+	for _, rule := range rules {
+		if rule.Type != item.Type {
+			continue
+		}
+
+		if rule.MaxDuration == 0 || item.Duration <= rule.MaxDuration {
+			return rule.Preset
+		}
+	}
+
+	return "original"
+}
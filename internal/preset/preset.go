@@ -0,0 +1,141 @@
+// Package preset defines named download presets (format/quality choices)
+// and the registry jobs are resolved against.
+package preset
+
+import "fmt"
+
+// Preset names a yt-dlp/gallery-dl format selection users can reference by
+// name instead of raw format strings.
+type Preset struct {
+	Name   string
+	Format string
+	// Extends names a base preset this one inherits from: an empty Format
+	// falls back to the base's, and PostProcess is the base's steps
+	// followed by this preset's own. Empty means no inheritance. Resolved
+	// once, at NewRegistry construction time.
+	Extends string
+	// PostProcess lists extra yt-dlp arguments appended after the format
+	// selection, e.g. "--embed-thumbnail".
+	PostProcess []string
+}
+
+// Registry resolves preset names to their fully-composed definition, with
+// Extends chains already flattened.
+type Registry struct {
+	presets map[string]Preset
+}
+
+// NewRegistry builds a Registry from the given presets, resolving each
+// one's Extends chain. It returns an error if a preset extends an unknown
+// name or the chain cycles back on itself.
+func NewRegistry(presets []Preset) (*Registry, error) {
+	raw := make(map[string]Preset, len(presets))
+	for _, p := range presets {
+		raw[p.Name] = p
+	}
+
+	resolved := make(map[string]Preset, len(presets))
+
+	for _, p := range presets {
+		r, err := resolvePreset(p.Name, raw, resolved, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+
+		resolved[p.Name] = r
+	}
+
+	return &Registry{presets: resolved}, nil
+}
+
+// resolvePreset composes name's Format/PostProcess from its Extends chain,
+// memoizing results into resolved and erroring on a cycle or missing base.
+func resolvePreset(name string, raw, resolved map[string]Preset, ancestors map[string]bool) (Preset, error) {
+	if r, ok := resolved[name]; ok {
+		return r, nil
+	}
+
+	if ancestors[name] {
+		return Preset{}, fmt.Errorf("preset %q: cycle in Extends chain", name)
+	}
+
+	p, ok := raw[name]
+	if !ok {
+		return Preset{}, fmt.Errorf("preset %q: not found", name)
+	}
+
+	if p.Extends == "" {
+		return p, nil
+	}
+
+	ancestors[name] = true
+	base, err := resolvePreset(p.Extends, raw, resolved, ancestors)
+	delete(ancestors, name)
+
+	if err != nil {
+		return Preset{}, fmt.Errorf("preset %q: %w", name, err)
+	}
+
+	merged := p
+	if merged.Format == "" {
+		merged.Format = base.Format
+	}
+
+	merged.PostProcess = append(append([]string{}, base.PostProcess...), p.PostProcess...)
+
+	return merged, nil
+}
+
+// Deprecation describes a preset name kept resolvable for backward
+// compatibility, along with the migration hint and retirement date a
+// caller should plan around; see Deprecations.
+type Deprecation struct {
+	// Message is the migration hint surfaced in an enqueue response's
+	// warnings array, e.g. `preset "mp4" is deprecated, use "mp4-1080"
+	// instead`.
+	Message string
+	// Sunset is an RFC 8594 HTTP-date this preset name stops being
+	// accepted, set as the response's Sunset header; empty means no
+	// retirement date has been decided yet, same convention as
+	// middleware.Deprecated's sunset parameter.
+	Sunset string
+}
+
+// Deprecations maps a deprecated preset name to its migration hint,
+// checked by v1.enqueueJob so renaming a preset doesn't silently break a
+// bot still requesting the old name.
+var Deprecations = map[string]Deprecation{
+	"mp4": {Message: `preset "mp4" is deprecated, use "mp4-1080" instead`},
+}
+
+// DeprecationFor looks up name in Deprecations.
+func DeprecationFor(name string) (Deprecation, bool) {
+	d, ok := Deprecations[name]
+
+	return d, ok
+}
+
+// Get looks up a preset by name.
+func (r *Registry) Get(name string) (Preset, bool) {
+	p, ok := r.presets[name]
+
+	return p, ok
+}
+
+// Default returns the registry's built-in presets.
+func Default() *Registry {
+	r, err := NewRegistry([]Preset{
+		{Name: "original", Format: "bestvideo+bestaudio/best"},
+		{Name: "audio", Format: "bestaudio"},
+		{Name: "mp4-1080", Format: "bestvideo[height<=1080]+bestaudio/best[height<=1080]"},
+		{Name: "mp4-720", Format: "bestvideo[height<=720]+bestaudio/best[height<=720]"},
+	})
+	if err != nil {
+		// The built-in presets are a hardcoded literal with no Extends, so
+		// this can never actually fail; a panic here means the literal
+		// above was edited into an invalid state.
+		panic(err)
+	}
+
+	return r
+}
@@ -0,0 +1,6 @@
+package memory
+
+import "errors"
+
+// ErrNotFound is returned by in-memory repositories when a lookup misses.
+var ErrNotFound = errors.New("not found")
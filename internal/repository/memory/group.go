@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// GroupRepository is an in-memory repository.GroupRepository.
+type GroupRepository struct {
+	mu     sync.RWMutex
+	groups map[uuid.UUID]*entity.JobGroup
+}
+
+// NewGroupRepository builds an empty in-memory group repository.
+func NewGroupRepository() *GroupRepository {
+	return &GroupRepository{groups: make(map[uuid.UUID]*entity.JobGroup)}
+}
+
+func (r *GroupRepository) Create(_ context.Context, group *entity.JobGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.groups[group.ID] = group
+
+	return nil
+}
+
+func (r *GroupRepository) Get(_ context.Context, id uuid.UUID) (*entity.JobGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	group, ok := r.groups[id]
+	if !ok {
+		return nil, fmt.Errorf("group %s: %w", id, ErrNotFound)
+	}
+
+	return group, nil
+}
+
+func (r *GroupRepository) Update(_ context.Context, group *entity.JobGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.groups[group.ID]; !ok {
+		return fmt.Errorf("group %s: %w", group.ID, ErrNotFound)
+	}
+
+	r.groups[group.ID] = group
+
+	return nil
+}
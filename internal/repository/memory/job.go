@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// JobRepository is an in-memory repository.JobRepository, suitable for
+// single-instance deployments and tests.
+type JobRepository struct {
+	mu    sync.RWMutex
+	jobs  map[uuid.UUID]*entity.Job
+	dedup map[string]uuid.UUID
+	// labels maps a "key:value" label to the set of job IDs tagged with
+	// it, see ListByLabel.
+	labels map[string]map[uuid.UUID]struct{}
+}
+
+// NewJobRepository builds an empty in-memory job repository.
+func NewJobRepository() *JobRepository {
+	return &JobRepository{
+		jobs:   make(map[uuid.UUID]*entity.Job),
+		dedup:  make(map[string]uuid.UUID),
+		labels: make(map[string]map[uuid.UUID]struct{}),
+	}
+}
+
+func (r *JobRepository) Create(_ context.Context, job *entity.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs[job.ID] = job
+	r.dedup[entity.DedupKey(job.URL, job.Preset)] = job.ID
+	r.indexLabels(job)
+
+	return nil
+}
+
+func (r *JobRepository) Get(_ context.Context, id uuid.UUID) (*entity.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job %s: %w", id, ErrNotFound)
+	}
+
+	return job, nil
+}
+
+func (r *JobRepository) Update(_ context.Context, job *entity.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.jobs[job.ID]
+	if !ok {
+		return fmt.Errorf("job %s: %w", job.ID, ErrNotFound)
+	}
+
+	r.unindexLabels(old)
+	r.jobs[job.ID] = job
+	r.indexLabels(job)
+
+	return nil
+}
+
+func (r *JobRepository) List(_ context.Context) ([]*entity.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]*entity.Job, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		out = append(out, job)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+
+	return out, nil
+}
+
+func (r *JobRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if job, ok := r.jobs[id]; ok {
+		if key := entity.DedupKey(job.URL, job.Preset); r.dedup[key] == id {
+			delete(r.dedup, key)
+		}
+		r.unindexLabels(job)
+	}
+
+	delete(r.jobs, id)
+
+	return nil
+}
+
+func (r *JobRepository) GetByDedupKey(_ context.Context, key string) (*entity.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.dedup[key]
+	if !ok {
+		return nil, fmt.Errorf("job with dedup key %q: %w", key, ErrNotFound)
+	}
+
+	return r.jobs[id], nil
+}
+
+func (r *JobRepository) ListByLabel(_ context.Context, key, value string) ([]*entity.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := r.labels[labelKey(key, value)]
+
+	out := make([]*entity.Job, 0, len(ids))
+	for id := range ids {
+		if job, ok := r.jobs[id]; ok {
+			out = append(out, job)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+
+	return out, nil
+}
+
+func labelKey(key, value string) string {
+	return key + ":" + value
+}
+
+func (r *JobRepository) indexLabels(job *entity.Job) {
+	for k, v := range job.Labels {
+		key := labelKey(k, v)
+
+		if r.labels[key] == nil {
+			r.labels[key] = make(map[uuid.UUID]struct{})
+		}
+
+		r.labels[key][job.ID] = struct{}{}
+	}
+}
+
+func (r *JobRepository) unindexLabels(job *entity.Job) {
+	for k, v := range job.Labels {
+		delete(r.labels[labelKey(k, v)], job.ID)
+	}
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// KeyDefaultsRepository is an in-memory repository.KeyDefaultsRepository.
+type KeyDefaultsRepository struct {
+	mu       sync.RWMutex
+	defaults map[string]*entity.KeyDefaults
+}
+
+// NewKeyDefaultsRepository builds an empty in-memory key-defaults
+// repository.
+func NewKeyDefaultsRepository() *KeyDefaultsRepository {
+	return &KeyDefaultsRepository{defaults: make(map[string]*entity.KeyDefaults)}
+}
+
+func (r *KeyDefaultsRepository) Get(_ context.Context, key string) (*entity.KeyDefaults, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defaults, ok := r.defaults[key]
+	if !ok {
+		return nil, fmt.Errorf("key defaults: %w", ErrNotFound)
+	}
+
+	return defaults, nil
+}
+
+func (r *KeyDefaultsRepository) Set(_ context.Context, defaults *entity.KeyDefaults) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.defaults[defaults.Key] = defaults
+
+	return nil
+}
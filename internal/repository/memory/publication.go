@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// PublicationRepository is an in-memory repository.PublicationRepository.
+type PublicationRepository struct {
+	mu   sync.RWMutex
+	pubs map[uuid.UUID]*entity.Publication
+}
+
+// NewPublicationRepository builds an empty in-memory publication repository.
+func NewPublicationRepository() *PublicationRepository {
+	return &PublicationRepository{pubs: make(map[uuid.UUID]*entity.Publication)}
+}
+
+func (r *PublicationRepository) Create(_ context.Context, pub *entity.Publication) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pubs[pub.UUID] = pub
+
+	return nil
+}
+
+func (r *PublicationRepository) Get(_ context.Context, id uuid.UUID) (*entity.Publication, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pub, ok := r.pubs[id]
+	if !ok {
+		return nil, fmt.Errorf("publication %s: %w", id, ErrNotFound)
+	}
+
+	return pub, nil
+}
+
+func (r *PublicationRepository) GetByJobID(_ context.Context, jobID uuid.UUID) (*entity.Publication, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, pub := range r.pubs {
+		if pub.JobID == jobID {
+			return pub, nil
+		}
+	}
+
+	return nil, fmt.Errorf("publication for job %s: %w", jobID, ErrNotFound)
+}
+
+func (r *PublicationRepository) GetByFileSHA256(_ context.Context, sha256 string) (*entity.Publication, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, pub := range r.pubs {
+		if pub.FileSHA256 == sha256 {
+			return pub, nil
+		}
+	}
+
+	return nil, fmt.Errorf("publication with file hash %s: %w", sha256, ErrNotFound)
+}
+
+func (r *PublicationRepository) Update(_ context.Context, pub *entity.Publication) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pubs[pub.UUID]; !ok {
+		return fmt.Errorf("publication %s: %w", pub.UUID, ErrNotFound)
+	}
+
+	r.pubs[pub.UUID] = pub
+
+	return nil
+}
+
+func (r *PublicationRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.pubs, id)
+
+	return nil
+}
+
+func (r *PublicationRepository) ListExpiringFiles(_ context.Context, cutoff time.Time) ([]*entity.Publication, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*entity.Publication
+
+	for _, pub := range r.pubs {
+		if pub.Status == entity.PublicationStatusReady && pub.CreatedAt.Before(cutoff) {
+			out = append(out, pub)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *PublicationRepository) ListExpiredMetadata(_ context.Context, cutoff time.Time) ([]*entity.Publication, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*entity.Publication
+
+	for _, pub := range r.pubs {
+		if pub.Status == entity.PublicationStatusExpired && pub.ExpiresAt.Before(cutoff) {
+			out = append(out, pub)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *PublicationRepository) List(_ context.Context) ([]*entity.Publication, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]*entity.Publication, 0, len(r.pubs))
+	for _, pub := range r.pubs {
+		out = append(out, pub)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// ScheduleRepository is an in-memory repository.ScheduleRepository.
+type ScheduleRepository struct {
+	mu        sync.RWMutex
+	schedules map[uuid.UUID]*entity.Schedule
+}
+
+// NewScheduleRepository builds an empty in-memory schedule repository.
+func NewScheduleRepository() *ScheduleRepository {
+	return &ScheduleRepository{schedules: make(map[uuid.UUID]*entity.Schedule)}
+}
+
+func (r *ScheduleRepository) Create(_ context.Context, schedule *entity.Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.schedules[schedule.ID] = schedule
+
+	return nil
+}
+
+func (r *ScheduleRepository) Get(_ context.Context, id uuid.UUID) (*entity.Schedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedule, ok := r.schedules[id]
+	if !ok {
+		return nil, fmt.Errorf("schedule %s: %w", id, ErrNotFound)
+	}
+
+	return schedule, nil
+}
+
+func (r *ScheduleRepository) List(_ context.Context) ([]*entity.Schedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]*entity.Schedule, 0, len(r.schedules))
+	for _, schedule := range r.schedules {
+		out = append(out, schedule)
+	}
+
+	return out, nil
+}
+
+func (r *ScheduleRepository) Update(_ context.Context, schedule *entity.Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.schedules[schedule.ID]; !ok {
+		return fmt.Errorf("schedule %s: %w", schedule.ID, ErrNotFound)
+	}
+
+	r.schedules[schedule.ID] = schedule
+
+	return nil
+}
+
+func (r *ScheduleRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.schedules, id)
+
+	return nil
+}
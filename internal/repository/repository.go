@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// JobRepository persists jobs.
+type JobRepository interface {
+	Create(ctx context.Context, job *entity.Job) error
+	Get(ctx context.Context, id uuid.UUID) (*entity.Job, error)
+	Update(ctx context.Context, job *entity.Job) error
+	// List returns every known job, newest first. Used by diagnostics (see
+	// the admin support-bundle endpoint), not the hot path.
+	List(ctx context.Context) ([]*entity.Job, error)
+	// Delete removes a job record entirely, e.g. for a GDPR-style purge.
+	Delete(ctx context.Context, id uuid.UUID) error
+	// GetByDedupKey returns the most recently created job for the given
+	// dedup key (see entity.DedupKey), used by EnqueueOptions.Dedupe to
+	// detect a duplicate request for the same URL+preset without the job
+	// ID itself having to encode that information.
+	GetByDedupKey(ctx context.Context, key string) (*entity.Job, error)
+	// ListByLabel returns every job tagged with label key=value (see
+	// entity.Job.Labels), newest first.
+	ListByLabel(ctx context.Context, key, value string) ([]*entity.Job, error)
+}
+
+// PublicationRepository persists publications, the durable record of what a
+// job produced.
+type PublicationRepository interface {
+	Create(ctx context.Context, pub *entity.Publication) error
+	Get(ctx context.Context, id uuid.UUID) (*entity.Publication, error)
+	// GetByJobID returns the publication produced by job jobID, if any.
+	GetByJobID(ctx context.Context, jobID uuid.UUID) (*entity.Publication, error)
+	// GetByFileSHA256 returns the publication whose file hashes to sha256,
+	// used to serve files under the content-addressed /v1/files/{sha256}
+	// route.
+	GetByFileSHA256(ctx context.Context, sha256 string) (*entity.Publication, error)
+	Update(ctx context.Context, pub *entity.Publication) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListExpiringFiles returns ready publications created before cutoff,
+	// i.e. due to have their file removed by the retention sweep.
+	ListExpiringFiles(ctx context.Context, cutoff time.Time) ([]*entity.Publication, error)
+	// ListExpiredMetadata returns expired publications whose ExpiresAt is
+	// before cutoff, i.e. due for full deletion.
+	ListExpiredMetadata(ctx context.Context, cutoff time.Time) ([]*entity.Publication, error)
+	// List returns every known publication. Used by diagnostics and the
+	// admin purge endpoint, not the hot path.
+	List(ctx context.Context) ([]*entity.Publication, error)
+}
+
+// GroupRepository persists job groups.
+type GroupRepository interface {
+	Create(ctx context.Context, group *entity.JobGroup) error
+	Get(ctx context.Context, id uuid.UUID) (*entity.JobGroup, error)
+	Update(ctx context.Context, group *entity.JobGroup) error
+}
+
+// ScheduleRepository persists recurring-download schedules.
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *entity.Schedule) error
+	Get(ctx context.Context, id uuid.UUID) (*entity.Schedule, error)
+	List(ctx context.Context) ([]*entity.Schedule, error)
+	Update(ctx context.Context, schedule *entity.Schedule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// KeyDefaultsRepository persists each API key's default enqueue options;
+// see entity.KeyDefaults.
+type KeyDefaultsRepository interface {
+	// Get returns key's stored defaults, or an error (memory.ErrNotFound in
+	// the in-memory implementation) if it has none set yet.
+	Get(ctx context.Context, key string) (*entity.KeyDefaults, error)
+	// Set stores defaults, overwriting whatever was previously set for its
+	// Key.
+	Set(ctx context.Context, defaults *entity.KeyDefaults) error
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ActiveHours bounds a recurring daily local-time window during which
+// JobService.EnqueueWithOptions may submit new jobs to the worker pool,
+// e.g. to keep downloads off a connection during business hours; see
+// JobService.SetActiveHours.
+type ActiveHours struct {
+	start, end time.Duration // offsets since local midnight
+}
+
+// ParseActiveHours parses a "HH:MM-HH:MM" window, as config.Job.ActiveHours
+// holds it. A window whose start is after its end wraps past midnight
+// (e.g. "22:00-06:00" is active overnight).
+func ParseActiveHours(s string) (*ActiveHours, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid active hours window %q: want HH:MM-HH:MM", s)
+	}
+
+	startOffset, err := parseClock(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid active hours window %q: %w", s, err)
+	}
+
+	endOffset, err := parseClock(end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid active hours window %q: %w", s, err)
+	}
+
+	return &ActiveHours{start: startOffset, end: endOffset}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// sinceMidnight returns how far t is into its own local day.
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// Contains reports whether t falls within the window.
+func (w *ActiveHours) Contains(t time.Time) bool {
+	now := sinceMidnight(t)
+
+	if w.start <= w.end {
+		return now >= w.start && now < w.end
+	}
+
+	return now >= w.start || now < w.end
+}
+
+// NextStart returns the next time at or after t that the window opens, or
+// t itself if it's already inside the window.
+func (w *ActiveHours) NextStart(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	start := midnight.Add(w.start)
+
+	if !start.After(t) {
+		start = start.Add(24 * time.Hour)
+	}
+
+	return start
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseActiveHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  string
+		wantErr bool
+	}{
+		{name: "plain window", window: "09:00-17:00"},
+		{name: "overnight window", window: "22:00-06:00"},
+		{name: "missing dash", window: "09:00", wantErr: true},
+		{name: "invalid start", window: "9am-17:00", wantErr: true},
+		{name: "invalid end", window: "09:00-5pm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseActiveHours(tt.window)
+			if tt.wantErr && err == nil {
+				t.Fatalf("ParseActiveHours(%q): want error, got nil", tt.window)
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Fatalf("ParseActiveHours(%q): %v", tt.window, err)
+			}
+		})
+	}
+}
+
+func TestActiveHoursContains(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 5, hour, minute, 0, 0, time.UTC)
+	}
+
+	plain, err := ParseActiveHours("09:00-17:00")
+	if err != nil {
+		t.Fatalf("ParseActiveHours: %v", err)
+	}
+
+	overnight, err := ParseActiveHours("22:00-06:00")
+	if err != nil {
+		t.Fatalf("ParseActiveHours: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		window *ActiveHours
+		t      time.Time
+		want   bool
+	}{
+		{name: "plain: inside window", window: plain, t: day(12, 0), want: true},
+		{name: "plain: at start is inclusive", window: plain, t: day(9, 0), want: true},
+		{name: "plain: at end is exclusive", window: plain, t: day(17, 0), want: false},
+		{name: "plain: before window", window: plain, t: day(8, 59), want: false},
+		{name: "overnight: before midnight", window: overnight, t: day(23, 0), want: true},
+		{name: "overnight: after midnight", window: overnight, t: day(3, 0), want: true},
+		{name: "overnight: at start is inclusive", window: overnight, t: day(22, 0), want: true},
+		{name: "overnight: at end is exclusive", window: overnight, t: day(6, 0), want: false},
+		{name: "overnight: outside window", window: overnight, t: day(12, 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(tt.t); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.t.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveHoursNextStart(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 5, hour, minute, 0, 0, time.UTC)
+	}
+
+	plain, err := ParseActiveHours("09:00-17:00")
+	if err != nil {
+		t.Fatalf("ParseActiveHours: %v", err)
+	}
+
+	if got := plain.NextStart(day(12, 0)); !got.Equal(day(12, 0)) {
+		t.Errorf("NextStart inside window = %v, want unchanged %v", got, day(12, 0))
+	}
+
+	if got, want := plain.NextStart(day(18, 0)), day(9, 0).Add(24*time.Hour); !got.Equal(want) {
+		t.Errorf("NextStart after window = %v, want %v", got, want)
+	}
+
+	if got, want := plain.NextStart(day(3, 0)), day(9, 0); !got.Equal(want) {
+		t.Errorf("NextStart before window = %v, want %v", got, want)
+	}
+}
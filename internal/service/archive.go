@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/crawler"
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/repository"
+)
+
+// ArchiveService drives "archive this whole account" operations: it probes
+// an account/channel URL for its items and enqueues one job per item,
+// pacing enqueues so it doesn't look like a scrape burst to the source.
+type ArchiveService struct {
+	groups repository.GroupRepository
+	jobs   *JobService
+	probe  crawler.Prober
+	pace   time.Duration
+}
+
+// NewArchiveService builds an ArchiveService. pace is the minimum delay
+// between enqueuing consecutive items.
+func NewArchiveService(jobs *JobService, groups repository.GroupRepository, probe crawler.Prober, pace time.Duration) *ArchiveService {
+	return &ArchiveService{jobs: jobs, groups: groups, probe: probe, pace: pace}
+}
+
+// Archive probes accountURL, creates a group and enqueues one job per
+// discovered item in the background, spaced by ArchiveService.pace.
+func (s *ArchiveService) Archive(ctx context.Context, accountURL, preset string) (*entity.JobGroup, error) {
+	items, err := s.probe.Probe(ctx, accountURL, 0)
+	if err != nil {
+		return nil, fmt.Errorf("probe account: %w", err)
+	}
+
+	group := entity.NewJobGroup(accountURL, preset)
+	group.Total = len(items)
+
+	if err := s.groups.Create(ctx, group); err != nil {
+		return nil, fmt.Errorf("create group: %w", err)
+	}
+
+	go s.fill(context.WithoutCancel(ctx), group, items)
+
+	return group, nil
+}
+
+// Resume re-probes the archive's source and enqueues only items not yet
+// present in group.Items, picking up where a previous Archive left off.
+func (s *ArchiveService) Resume(ctx context.Context, groupID uuid.UUID) (*entity.JobGroup, error) {
+	group, err := s.groups.Get(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("get group: %w", err)
+	}
+
+	items, err := s.probe.Probe(ctx, group.Source, 0)
+	if err != nil {
+		return nil, fmt.Errorf("probe account: %w", err)
+	}
+
+	missing := missingItems(group, items)
+	group.Total = len(items)
+
+	go s.fill(context.WithoutCancel(ctx), group, missing)
+
+	return group, nil
+}
+
+// SyncResult reports the outcome of a differential Sync against the
+// locally recorded items of a group.
+type SyncResult struct {
+	New     []crawler.Item `json:"new"`
+	Removed []string       `json:"removed"`
+}
+
+// Sync re-probes group's source and enqueues jobs for any item not already
+// present locally (by canonical media ID), without deleting jobs for items
+// that have disappeared from the source — it only reports them as removed.
+func (s *ArchiveService) Sync(ctx context.Context, groupID uuid.UUID) (*entity.JobGroup, *SyncResult, error) {
+	group, err := s.groups.Get(ctx, groupID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get group: %w", err)
+	}
+
+	items, err := s.probe.Probe(ctx, group.Source, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("probe account: %w", err)
+	}
+
+	result := &SyncResult{New: missingItems(group, items)}
+
+	present := make(map[string]bool, len(items))
+	for _, item := range items {
+		present[itemKey(item)] = true
+	}
+
+	for key := range group.Items {
+		if !present[key] {
+			result.Removed = append(result.Removed, key)
+		}
+	}
+
+	group.Total = len(items)
+
+	go s.fill(context.WithoutCancel(ctx), group, result.New)
+
+	return group, result, nil
+}
+
+// itemKey returns the canonical media ID to dedupe an item by, falling back
+// to its URL when no extractor-provided ID is available.
+func itemKey(item crawler.Item) string {
+	if item.ID != "" {
+		return item.ID
+	}
+
+	return item.URL
+}
+
+// missingItems returns the items whose key is not already recorded in
+// group.Items.
+func missingItems(group *entity.JobGroup, items []crawler.Item) []crawler.Item {
+	var missing []crawler.Item
+
+	for _, item := range items {
+		if _, ok := group.Items[itemKey(item)]; !ok {
+			missing = append(missing, item)
+		}
+	}
+
+	return missing
+}
+
+func (s *ArchiveService) fill(ctx context.Context, group *entity.JobGroup, items []crawler.Item) {
+	for _, item := range items {
+		job, err := s.jobs.Enqueue(ctx, item.URL, group.Preset)
+		if err == nil {
+			group.JobIDs = append(group.JobIDs, job.ID)
+			group.Items[itemKey(item)] = job.ID
+		}
+
+		_ = s.groups.Update(ctx, group)
+
+		time.Sleep(s.pace)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// AuthService holds the set of API keys and their scopes loaded from the
+// auth keys file (a JSON array of entity.APIKey), and answers scope checks
+// for the route-level enforcement in v1.Handler. With no keys file ever
+// loaded, Configured reports false and every scope check passes, preserving
+// the single-shared-secret-free default of treating ClientKeyHeader as a
+// plain identity rather than a credential.
+type AuthService struct {
+	mu          sync.RWMutex
+	keys        map[string]entity.APIKey
+	configured  bool
+	lastModTime time.Time
+}
+
+// NewAuthService builds an empty, unconfigured AuthService.
+func NewAuthService() *AuthService {
+	return &AuthService{keys: make(map[string]entity.APIKey)}
+}
+
+// Load reads path as a JSON array of entity.APIKey and replaces the
+// in-memory key set atomically. An empty path is a no-op, leaving the
+// service unconfigured.
+func (s *AuthService) Load(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read keys file: %w", err)
+	}
+
+	var keys []entity.APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("unmarshal keys file: %w", err)
+	}
+
+	byKey := make(map[string]entity.APIKey, len(keys))
+	for _, k := range keys {
+		byKey[k.Key] = k
+	}
+
+	s.mu.Lock()
+	s.keys = byKey
+	s.configured = true
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Configured reports whether a keys file has ever been loaded successfully.
+// While false, Authorize grants every request.
+func (s *AuthService) Configured() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.configured
+}
+
+// Authorize reports whether key grants scope. It always returns true while
+// the service is unconfigured; see Configured.
+func (s *AuthService) Authorize(key, scope string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.configured {
+		return true
+	}
+
+	apiKey, ok := s.keys[key]
+	if !ok {
+		return false
+	}
+
+	return apiKey.HasScope(scope)
+}
+
+// WatchFile polls path on the given interval and reloads it whenever its
+// modification time changes, so an operator can add, remove or re-scope a
+// key without restarting the server. It blocks until ctx is cancelled.
+func (s *AuthService) WatchFile(ctx context.Context, path string, interval time.Duration, log *slog.Logger) {
+	if path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reloadIfChanged(path, log)
+		}
+	}
+}
+
+func (s *AuthService) reloadIfChanged(path string, log *slog.Logger) {
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Warn("auth keys file: stat failed", "path", path, "error", err)
+		return
+	}
+
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.lastModTime)
+	s.mu.RUnlock()
+
+	if unchanged {
+		return
+	}
+
+	if err := s.Load(path); err != nil {
+		log.Error("auth keys file: reload failed", "path", path, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.lastModTime = info.ModTime()
+	s.mu.Unlock()
+
+	log.Info("auth keys file: reloaded", "path", path)
+}
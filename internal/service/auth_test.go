@@ -0,0 +1,103 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+func TestAuthServiceUnconfiguredGrantsEverything(t *testing.T) {
+	s := NewAuthService()
+
+	if s.Configured() {
+		t.Fatal("Configured() = true before any Load call")
+	}
+
+	if !s.Authorize("anything", entity.ScopeAdmin) {
+		t.Error("Authorize on an unconfigured service should grant every scope")
+	}
+}
+
+func TestAuthServiceScoping(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+
+	keys := `[
+		{"key": "read-only", "scopes": ["read"]},
+		{"key": "enqueue-and-read", "scopes": ["enqueue", "read"]},
+		{"key": "admin-key", "scopes": ["admin"]}
+	]`
+
+	if err := os.WriteFile(path, []byte(keys), 0o600); err != nil {
+		t.Fatalf("write keys file: %v", err)
+	}
+
+	s := NewAuthService()
+	if err := s.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if !s.Configured() {
+		t.Fatal("Configured() = false after a successful Load")
+	}
+
+	tests := []struct {
+		name  string
+		key   string
+		scope string
+		want  bool
+	}{
+		{name: "read key has read", key: "read-only", scope: entity.ScopeRead, want: true},
+		{name: "read key lacks enqueue", key: "read-only", scope: entity.ScopeEnqueue, want: false},
+		{name: "enqueue key has enqueue", key: "enqueue-and-read", scope: entity.ScopeEnqueue, want: true},
+		{name: "enqueue key has read too", key: "enqueue-and-read", scope: entity.ScopeRead, want: true},
+		{name: "enqueue key lacks admin", key: "enqueue-and-read", scope: entity.ScopeAdmin, want: false},
+		{name: "admin key implies read", key: "admin-key", scope: entity.ScopeRead, want: true},
+		{name: "admin key implies enqueue", key: "admin-key", scope: entity.ScopeEnqueue, want: true},
+		{name: "unknown key is denied", key: "no-such-key", scope: entity.ScopeRead, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Authorize(tt.key, tt.scope); got != tt.want {
+				t.Errorf("Authorize(%q, %q) = %v, want %v", tt.key, tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthServiceLoadReplacesKeySet(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+
+	if err := os.WriteFile(path, []byte(`[{"key": "alice", "scopes": ["read"]}]`), 0o600); err != nil {
+		t.Fatalf("write keys file: %v", err)
+	}
+
+	s := NewAuthService()
+	if err := s.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if !s.Authorize("alice", entity.ScopeRead) {
+		t.Fatal("alice should have read scope after the first Load")
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"key": "bob", "scopes": ["enqueue"]}]`), 0o600); err != nil {
+		t.Fatalf("rewrite keys file: %v", err)
+	}
+
+	if err := s.Load(path); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	if s.Authorize("alice", entity.ScopeRead) {
+		t.Error("alice should no longer be a known key after a reload drops her")
+	}
+
+	if !s.Authorize("bob", entity.ScopeEnqueue) {
+		t.Error("bob should have enqueue scope after the reload")
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/repository/memory"
+)
+
+func newDedupeTestService(t *testing.T) (*JobService, context.Context) {
+	t.Helper()
+
+	jobs := memory.NewJobRepository()
+	svc := NewJobService(jobs, nil, nil, nil, "")
+
+	return svc, context.Background()
+}
+
+func TestDedupeTargetForceNewAndEmptyNeverReuse(t *testing.T) {
+	svc, ctx := newDedupeTestService(t)
+
+	existing := entity.NewJob("https://example.com/clip", "default")
+	if err := svc.jobs.Create(ctx, existing); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	for _, policy := range []DedupePolicy{"", DedupeForceNew} {
+		target, err := svc.dedupeTarget(ctx, policy, existing.URL, existing.Preset)
+		if err != nil {
+			t.Fatalf("dedupeTarget(%q): %v", policy, err)
+		}
+
+		if target != nil {
+			t.Errorf("dedupeTarget(%q) = %v, want nil so a new job is created", policy, target)
+		}
+	}
+}
+
+func TestDedupeTargetReuseAlwaysReturnsExisting(t *testing.T) {
+	svc, ctx := newDedupeTestService(t)
+
+	existing := entity.NewJob("https://example.com/clip", "default")
+	existing.CreatedAt = time.Now().Add(-48 * time.Hour)
+
+	if err := svc.jobs.Create(ctx, existing); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	target, err := svc.dedupeTarget(ctx, DedupeReuse, existing.URL, existing.Preset)
+	if err != nil {
+		t.Fatalf("dedupeTarget: %v", err)
+	}
+
+	if target == nil || target.ID != existing.ID {
+		t.Errorf("dedupeTarget(DedupeReuse) = %v, want the existing job regardless of age", target)
+	}
+}
+
+func TestDedupeTargetReuseIfFresh(t *testing.T) {
+	svc, ctx := newDedupeTestService(t)
+
+	fresh := entity.NewJob("https://example.com/fresh", "default")
+	fresh.CreatedAt = time.Now().Add(-5 * time.Minute)
+
+	stale := entity.NewJob("https://example.com/stale", "default")
+	stale.CreatedAt = time.Now().Add(-5 * time.Hour)
+
+	for _, j := range []*entity.Job{fresh, stale} {
+		if err := svc.jobs.Create(ctx, j); err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+	}
+
+	target, err := svc.dedupeTarget(ctx, "reuse_if_fresh:1h", fresh.URL, fresh.Preset)
+	if err != nil {
+		t.Fatalf("dedupeTarget(fresh): %v", err)
+	}
+
+	if target == nil || target.ID != fresh.ID {
+		t.Errorf("dedupeTarget(fresh, reuse_if_fresh:1h) = %v, want the existing job reused", target)
+	}
+
+	target, err = svc.dedupeTarget(ctx, "reuse_if_fresh:1h", stale.URL, stale.Preset)
+	if err != nil {
+		t.Fatalf("dedupeTarget(stale): %v", err)
+	}
+
+	if target != nil {
+		t.Errorf("dedupeTarget(stale, reuse_if_fresh:1h) = %v, want nil past the freshness window", target)
+	}
+}
+
+func TestDedupeTargetNoExistingJobCreatesNew(t *testing.T) {
+	svc, ctx := newDedupeTestService(t)
+
+	target, err := svc.dedupeTarget(ctx, DedupeReuse, "https://example.com/never-enqueued", "default")
+	if err != nil {
+		t.Fatalf("dedupeTarget: %v", err)
+	}
+
+	if target != nil {
+		t.Errorf("dedupeTarget with no prior job = %v, want nil", target)
+	}
+}
+
+func TestDedupeTargetUnknownPolicy(t *testing.T) {
+	svc, ctx := newDedupeTestService(t)
+
+	existing := entity.NewJob("https://example.com/clip", "default")
+	if err := svc.jobs.Create(ctx, existing); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	if _, err := svc.dedupeTarget(ctx, "bogus", existing.URL, existing.Preset); err == nil {
+		t.Error("dedupeTarget with an unknown policy should error, got nil")
+	}
+}
+
+func TestDedupeTargetInvalidFreshnessWindow(t *testing.T) {
+	svc, ctx := newDedupeTestService(t)
+
+	existing := entity.NewJob("https://example.com/clip", "default")
+	if err := svc.jobs.Create(ctx, existing); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	if _, err := svc.dedupeTarget(ctx, "reuse_if_fresh:not-a-duration", existing.URL, existing.Preset); err == nil {
+		t.Error("dedupeTarget with an unparseable freshness window should error, got nil")
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// FaultInjector simulates downloader failure modes (slow, flaky, partial
+// output, huge stdout) for end-to-end resilience testing of the queue,
+// retries and cleanup in staging, without touching a real extractor. It's
+// only consulted by YtDlpRunner when config.Job.FaultInjectionEnabled is
+// set, so a job's FaultMode can never affect production.
+type FaultInjector struct{}
+
+// Inject simulates job.FaultMode, if recognized. handled is true when the
+// caller should skip the real download because Inject already played the
+// part of running it (successfully or not).
+func (FaultInjector) Inject(ctx context.Context, job *entity.Job) (handled bool, err error) {
+	switch job.FaultMode {
+	case "":
+		return false, nil
+	case "slow":
+		select {
+		case <-time.After(30 * time.Second):
+			return true, nil
+		case <-ctx.Done():
+			return true, ctx.Err()
+		}
+	case "flaky":
+		if rand.Intn(2) == 0 {
+			return true, fmt.Errorf("simulated flaky downloader failure")
+		}
+
+		return true, nil
+	case "partial":
+		job.AddEvent("fault_injected", "simulated partial/truncated output")
+		return true, nil
+	case "huge_stdout":
+		job.AddEvent("fault_injected", strings.Repeat("x", 64*1024))
+		return true, nil
+	default:
+		return true, fmt.Errorf("unknown fault mode %q", job.FaultMode)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// Hub fans out job status updates to subscribers, e.g. WebSocket clients
+// watching specific job UUIDs (see delivery/http/v1's /v1/ws handler).
+type Hub struct {
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[chan entity.Job]struct{}
+}
+
+// NewHub builds an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[uuid.UUID]map[chan entity.Job]struct{})}
+}
+
+// Subscribe returns a channel that receives every UpdateJobStatus(jobID, ...)
+// call until unsubscribe is invoked. The channel is buffered so a slow
+// reader doesn't block the publisher; Publish drops updates a full
+// subscriber channel can't keep up with.
+func (h *Hub) Subscribe(jobID uuid.UUID) (ch chan entity.Job, unsubscribe func()) {
+	ch = make(chan entity.Job, 16)
+
+	h.mu.Lock()
+	if h.subs[jobID] == nil {
+		h.subs[jobID] = make(map[chan entity.Job]struct{})
+	}
+	h.subs[jobID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs[jobID], ch)
+		if len(h.subs[jobID]) == 0 {
+			delete(h.subs, jobID)
+		}
+		h.mu.Unlock()
+		close(ch)
+	}
+}
+
+// UpdateJobStatus broadcasts job's current state to every subscriber
+// watching job.ID.
+func (h *Hub) UpdateJobStatus(job entity.Job) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[job.ID] {
+		select {
+		case ch <- job:
+		default:
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/repository"
+)
+
+// ImportLineResult reports the outcome of importing a single line of a
+// batch file.
+type ImportLineResult struct {
+	Line  int    `json:"line"`
+	URL   string `json:"url"`
+	JobID string `json:"job_id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ImportService creates job groups from batch URL lists, mirroring yt-dlp's
+// --batch-file workflow. Each non-empty, non-comment line is treated as
+// "url[,preset]"; blank lines and lines starting with "#" are skipped.
+type ImportService struct {
+	jobs   *JobService
+	groups repository.GroupRepository
+}
+
+// NewImportService builds an ImportService.
+func NewImportService(jobs *JobService, groups repository.GroupRepository) *ImportService {
+	return &ImportService{jobs: jobs, groups: groups}
+}
+
+// Import reads a batch file (.txt/.csv) from r, enqueues a job per valid
+// line and groups them under a single entity.JobGroup. Parse/enqueue
+// failures on individual lines are reported in the returned results rather
+// than aborting the whole import.
+func (s *ImportService) Import(ctx context.Context, source, defaultPreset string, r io.Reader) (*entity.JobGroup, []ImportLineResult, error) {
+	group := entity.NewJobGroup(source, defaultPreset)
+
+	var results []ImportLineResult
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		url, preset := parseImportLine(line, defaultPreset)
+
+		result := ImportLineResult{Line: lineNo, URL: url}
+
+		job, err := s.jobs.Enqueue(ctx, url, preset)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.JobID = job.ID.String()
+			group.JobIDs = append(group.JobIDs, job.ID)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("read batch file: %w", err)
+	}
+
+	if err := s.groups.Create(ctx, group); err != nil {
+		return nil, nil, fmt.Errorf("create job group: %w", err)
+	}
+
+	return group, results, nil
+}
+
+// parseImportLine splits a "url,preset" CSV-style line, falling back to
+// defaultPreset when no preset column is present.
+func parseImportLine(line, defaultPreset string) (url, preset string) {
+	parts := strings.SplitN(line, ",", 2)
+
+	url = strings.TrimSpace(parts[0])
+	preset = defaultPreset
+
+	if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
+		preset = strings.TrimSpace(parts[1])
+	}
+
+	return url, preset
+}
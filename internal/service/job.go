@@ -0,0 +1,1162 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/crawler"
+	"github.com/evan-sm/daunrodo/internal/crawler/reddit"
+	"github.com/evan-sm/daunrodo/internal/entity"
+	presetpkg "github.com/evan-sm/daunrodo/internal/preset"
+	"github.com/evan-sm/daunrodo/internal/repository"
+	"github.com/evan-sm/daunrodo/internal/worker"
+	"github.com/evan-sm/daunrodo/pkg/clock"
+)
+
+// autoPreset is the sentinel preset name that triggers probe-based
+// auto-selection in Enqueue, see PresetAutoSelector.
+const autoPreset = "auto"
+
+// JobService owns job lifecycle: creation, status transitions and lookups.
+type JobService struct {
+	jobs      repository.JobRepository
+	groups    repository.GroupRepository
+	prober    crawler.Prober
+	autoRules []presetpkg.AutoRule
+	pool      *worker.Pool
+	notifier  *WebhookNotifier
+	runner    *YtDlpRunner
+	hub       *Hub
+	// clock is consulted wherever this service needs "now", so tests can
+	// control time deterministically (e.g. active-hours edge cases) via
+	// clock.NewFake instead of real sleeps.
+	clock clock.Clock
+	// metadataProber backs ProbeMetadata; nil (the default) means probing
+	// isn't available. See SetMetadataProber.
+	metadataProber crawler.MetadataProber
+	// stats accumulates per-domain average job duration, used by
+	// EnqueueWithOptions to estimate a new job's wait time.
+	stats *durationStats
+	// throughput tracks aggregate current download throughput across
+	// finished attempts; see PoolStats.
+	throughput *throughputStats
+	// activeHours, if set, restricts EnqueueWithOptions to submitting new
+	// jobs to the pool only inside the window; see SetActiveHours.
+	activeHours *ActiveHours
+	// defaultPresetsByExtractor maps a source domain to the preset used
+	// when a request omits one; see SetDefaultPresetsByExtractor.
+	defaultPresetsByExtractor map[string]string
+	// playlistArchiveDir holds one yt-dlp download-archive file per
+	// playlist/channel URL enqueued via EnqueuePlaylist, keyed by a hash of
+	// the URL so a repeat submission of the same source reuses it and
+	// skips items already fetched; persisted to disk so it survives
+	// restarts. Empty disables this.
+	playlistArchiveDir string
+	// keyDefaults resolves an API key's server-stored default enqueue
+	// options; nil (the default) means no key ever has any. See
+	// SetKeyDefaults.
+	keyDefaults *KeyDefaultsService
+	// redditResolver, if set, lets EnqueueWithOptions resolve a Reddit
+	// crosspost to its original submission and expand a gallery post into
+	// one job per image, before the URL ever reaches the downloader. See
+	// SetRedditResolver.
+	redditResolver *reddit.Resolver
+	// maxPlaylistItems caps how many items EnqueuePlaylist/enqueueAlbum
+	// expand a source into and enqueueRedditGallery's gallery URL list is
+	// truncated to, when a request doesn't set its own
+	// EnqueueOptions.MaxItems; 0 means unlimited. See
+	// config.Job.MaxPlaylistItems and SetMaxPlaylistItems.
+	maxPlaylistItems int
+	// cancelFns holds the cancellation func for each currently-running
+	// job's per-job context, registered by YtDlpRunner.Run; see Cancel.
+	cancelFnsMu sync.Mutex
+	cancelFns   map[uuid.UUID]context.CancelFunc
+}
+
+// NewJobService builds a JobService. prober and autoRules may be nil/empty
+// when preset "auto" is not used. groups backs EnqueuePlaylist's child-job
+// tracking; it may be nil for callers that never use playlist expansion.
+// playlistArchiveDir is where EnqueuePlaylist keeps its per-URL
+// download-archive files; empty disables them. The worker pool is wired in
+// separately via SetPool once it exists, since the pool's Runner typically
+// depends back on the JobService to update job status.
+func NewJobService(jobs repository.JobRepository, groups repository.GroupRepository, prober crawler.Prober, autoRules []presetpkg.AutoRule, playlistArchiveDir string) *JobService {
+	return &JobService{
+		jobs:               jobs,
+		groups:             groups,
+		prober:             prober,
+		autoRules:          autoRules,
+		cancelFns:          make(map[uuid.UUID]context.CancelFunc),
+		stats:              newDurationStats(),
+		throughput:         newThroughputStats(),
+		playlistArchiveDir: playlistArchiveDir,
+		clock:              clock.New(),
+	}
+}
+
+// SetClock overrides the clock used for active-hours checks; tests can pass
+// a clock.NewFake to exercise window-boundary edge cases deterministically.
+// Production wiring never needs this, since NewJobService already defaults
+// to clock.New().
+func (s *JobService) SetClock(clk clock.Clock) {
+	s.clock = clk
+}
+
+// RecordThroughput folds one downloader attempt's bytes transferred over d
+// into the aggregate throughput estimate; called by YtDlpRunner once per
+// attempt. See PoolStats.
+func (s *JobService) RecordThroughput(bytes int64, d time.Duration) {
+	s.throughput.record(bytes, d)
+}
+
+// recordDuration folds a finished job's run time into the per-domain
+// average used by EstimateWait. Called by YtDlpRunner once a job reaches
+// JobStatusDone or JobStatusError.
+func (s *JobService) recordDuration(job *entity.Job) {
+	if job.StartedAt == nil {
+		return
+	}
+
+	s.stats.record(worker.Domain(job.URL), time.Since(*job.StartedAt))
+}
+
+// EstimateWait estimates how long a newly queued job for url will wait
+// before a worker picks it up, from the current queue backlog, the pool's
+// active worker count and an EWMA of historical job durations for url's
+// domain (see durationStats), along with a confidence grading for that
+// estimate. queuePosition is 1-based; all three results are zero/
+// WaitConfidenceNone if no pool is wired.
+func (s *JobService) EstimateWait(url string) (queuePosition int, wait time.Duration, confidence WaitConfidence) {
+	if s.pool == nil {
+		return 0, 0, WaitConfidenceNone
+	}
+
+	stats := s.pool.Stats()
+
+	queuePosition = stats.QueueLen + 1
+
+	workers := stats.ActiveWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	avg, confidence := s.stats.average(worker.Domain(url))
+
+	// A long-stalled queue (the oldest queued job has already waited more
+	// than twice the domain's average duration) means the backlog isn't draining
+	// at its normal rate, so a plain "position * avg / workers" projection
+	// would understate the wait; downgrade confidence instead of claiming
+	// a number we don't believe.
+	if stats.OldestQueuedAge > avg*2 && confidence != WaitConfidenceNone {
+		confidence = WaitConfidenceLow
+	}
+
+	return queuePosition, time.Duration(queuePosition) * avg / time.Duration(workers), confidence
+}
+
+// SetPool wires the worker pool that newly enqueued jobs are submitted to.
+func (s *JobService) SetPool(pool *worker.Pool) {
+	s.pool = pool
+}
+
+// SetActiveHours restricts EnqueueWithOptions to submitting new jobs to the
+// pool only during window; a job enqueued outside it is held as
+// JobStatusScheduled until the window opens. nil (the default) disables
+// the restriction, submitting jobs immediately as before.
+func (s *JobService) SetActiveHours(window *ActiveHours) {
+	s.activeHours = window
+}
+
+// SetDefaultPresetsByExtractor configures the preset picked for a request
+// that omits one, keyed by the source URL's host (see worker.Domain), e.g.
+// {"tiktok.com": "original", "soundcloud.com": "audio", "youtube.com":
+// "mp4-1080"}. A host with no entry falls back to "original". nil (the
+// default) makes every request omitting a preset fall back to "original".
+func (s *JobService) SetDefaultPresetsByExtractor(presets map[string]string) {
+	s.defaultPresetsByExtractor = presets
+}
+
+// SetKeyDefaults wires the service that resolves an API key's server-stored
+// default enqueue options; nil (the default) disables the feature, leaving
+// every request to specify its own options. See EnqueueOptions.APIKey.
+func (s *JobService) SetKeyDefaults(keyDefaults *KeyDefaultsService) {
+	s.keyDefaults = keyDefaults
+}
+
+// SetMaxPlaylistItems configures the server-wide default cap on how many
+// items a playlist/gallery/album expansion enqueues; 0 (the default)
+// leaves it unlimited. See maxPlaylistItems.
+func (s *JobService) SetMaxPlaylistItems(n int) {
+	s.maxPlaylistItems = n
+}
+
+// effectiveMaxItems resolves the cap that applies to a single
+// playlist/gallery/album expansion: a positive per-request override wins,
+// otherwise the server-wide default (0 if neither is set, meaning
+// unlimited).
+func (s *JobService) effectiveMaxItems(override int) int {
+	if override > 0 {
+		return override
+	}
+
+	return s.maxPlaylistItems
+}
+
+// SetMetadataProber wires the prober ProbeMetadata delegates to; nil (the
+// default) makes ProbeMetadata report an error instead of probing.
+func (s *JobService) SetMetadataProber(prober crawler.MetadataProber) {
+	s.metadataProber = prober
+}
+
+// SetRedditResolver wires in crosspost/gallery resolution for Reddit
+// submission URLs; see EnqueueWithOptions and reddit.Resolver.
+func (s *JobService) SetRedditResolver(resolver *reddit.Resolver) {
+	s.redditResolver = resolver
+}
+
+// applyKeyDefaults fills in any of opts' fields left empty by the caller
+// from apiKey's server-stored defaults, if any are configured; apiKey or a
+// missing KeyDefaultsService lookup leaves opts unchanged, the same way
+// dedupeTarget treats a lookup miss as "nothing to apply" rather than an
+// error.
+func (s *JobService) applyKeyDefaults(ctx context.Context, opts EnqueueOptions) EnqueueOptions {
+	if s.keyDefaults == nil || opts.APIKey == "" {
+		return opts
+	}
+
+	defaults, err := s.keyDefaults.Get(ctx, opts.APIKey)
+	if err != nil {
+		return opts
+	}
+
+	if opts.Preset == "" {
+		opts.Preset = defaults.Preset
+	}
+
+	if opts.Profile == "" {
+		opts.Profile = defaults.Profile
+	}
+
+	if opts.Webhook == "" {
+		opts.Webhook = defaults.Webhook
+	}
+
+	if opts.Labels == nil {
+		opts.Labels = defaults.Labels
+	}
+
+	return opts
+}
+
+// ParseDefaultPresetsByExtractor parses "domain=preset" pairs (as
+// config.Job.DefaultPresetsByExtractor holds them) into the map
+// SetDefaultPresetsByExtractor expects, skipping malformed entries since a
+// bad config value shouldn't take the server down.
+func ParseDefaultPresetsByExtractor(pairs []string) map[string]string {
+	presets := make(map[string]string, len(pairs))
+
+	for _, pair := range pairs {
+		domain, preset, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+
+		presets[strings.TrimSpace(domain)] = strings.TrimSpace(preset)
+	}
+
+	return presets
+}
+
+// defaultPresetForURL picks the configured default preset for url's host,
+// falling back to "original" when none is configured for it; see
+// SetDefaultPresetsByExtractor.
+func (s *JobService) defaultPresetForURL(url string) string {
+	if p, ok := s.defaultPresetsByExtractor[worker.Domain(url)]; ok {
+		return p
+	}
+
+	return "original"
+}
+
+// PoolStats returns a snapshot of the worker pool's current load, for the
+// admin queue introspection endpoint. The zero value is returned if no pool
+// is wired (e.g. in tests).
+func (s *JobService) PoolStats() worker.Stats {
+	if s.pool == nil {
+		return worker.Stats{}
+	}
+
+	return s.pool.Stats()
+}
+
+// RunnerOutputDir returns the temp directory id's downloader is currently
+// writing into, and whether it found one, if a runner is configured; see
+// YtDlpRunner.JobDir and GET /v1/jobs/{id}/stream.
+func (s *JobService) RunnerOutputDir(id uuid.UUID) (string, bool) {
+	if s.runner == nil {
+		return "", false
+	}
+
+	return s.runner.JobDir(id)
+}
+
+// PoolWorkers returns a snapshot of every worker goroutine, for the admin
+// stuck-worker diagnostics endpoint. nil is returned if no pool is wired
+// (e.g. in tests).
+func (s *JobService) PoolWorkers() []worker.WorkerInfo {
+	if s.pool == nil {
+		return nil
+	}
+
+	return s.pool.Workers()
+}
+
+// ThroughputBytesPerSec returns an exponentially-weighted average of the
+// bytes/sec each finished download attempt achieved, 0 if none has
+// completed an attempt yet. It is a per-attempt rate, not a sum across
+// jobs running at the same time. See PoolStats for the admin queue
+// introspection endpoint this feeds.
+func (s *JobService) ThroughputBytesPerSec() float64 {
+	return s.throughput.current()
+}
+
+// DrainPool stops the worker pool from accepting new jobs via Enqueue,
+// letting already-running jobs finish. It is irreversible for the life of
+// the process; restart the server to resume accepting jobs.
+func (s *JobService) DrainPool() {
+	if s.pool != nil {
+		s.pool.Drain()
+	}
+}
+
+// ShutdownPool drains the worker pool and waits up to timeout for
+// already-running jobs to finish, for a graceful SIGTERM handler. It
+// returns the jobs that were still queued (never picked up by a worker) so
+// the caller can persist them with worker.SaveQueue and restore them on the
+// next startup via RequeuePersisted.
+func (s *JobService) ShutdownPool(ctx context.Context, timeout time.Duration) []*entity.Job {
+	if s.pool == nil {
+		return nil
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return s.pool.Shutdown(shutdownCtx)
+}
+
+// RequeuePersisted re-creates jobs restored by worker.LoadQueue in the job
+// repository and resubmits them to the worker pool, so a server restart
+// picks up queued work a previous graceful shutdown had to set aside
+// instead of losing it. Jobs that fail to re-create are skipped.
+func (s *JobService) RequeuePersisted(ctx context.Context, jobs []*entity.Job) {
+	for _, job := range jobs {
+		if err := s.jobs.Create(ctx, job); err != nil {
+			continue
+		}
+
+		if s.pool != nil {
+			_ = s.pool.Submit(job)
+		}
+	}
+}
+
+// SetRunner wires the runner used to preview the command line a job would
+// execute, see DryRun.
+func (s *JobService) SetRunner(runner *YtDlpRunner) {
+	s.runner = runner
+}
+
+// SetHub wires the hub that job status updates are broadcast to, e.g. for
+// WebSocket subscribers; nil (the default) disables broadcasting.
+func (s *JobService) SetHub(hub *Hub) {
+	s.hub = hub
+}
+
+// SetNotifier wires the webhook notifier used to report job status
+// transitions; nil (the default) disables notifications entirely.
+func (s *JobService) SetNotifier(notifier *WebhookNotifier) {
+	s.notifier = notifier
+}
+
+// Notify reports event for job to its webhook, if any is configured. It
+// runs the delivery (with retries) in a goroutine so callers on the hot
+// path (status transitions) never block on a slow or unreachable receiver.
+func (s *JobService) Notify(ctx context.Context, job *entity.Job, event string) {
+	if s.notifier == nil {
+		return
+	}
+
+	jobCopy := *job
+
+	go s.notifier.Notify(context.WithoutCancel(ctx), &jobCopy, event)
+}
+
+// Enqueue creates a new pending job for the given URL and preset. It is a
+// convenience wrapper around EnqueueWithOptions for callers that don't need
+// the extra options (imports, archive fan-out, refetch, ...).
+func (s *JobService) Enqueue(ctx context.Context, url, preset string) (*entity.Job, error) {
+	return s.EnqueueWithOptions(ctx, EnqueueOptions{URL: url, Preset: preset})
+}
+
+// EnqueueOptions configures a job beyond the plain URL/preset pair.
+type EnqueueOptions struct {
+	URL    string
+	Preset string
+	// ClipStart/ClipEnd request a time range instead of the whole media,
+	// see entity.Job.ClipStart/ClipEnd.
+	ClipStart *time.Duration
+	ClipEnd   *time.Duration
+	Profile   string
+	// GifOutput requests an additional GIF/webp/avif artifact be produced
+	// alongside the original download, see entity.Artifact.
+	GifOutput *entity.GifOptions
+	// ImageTransform requests post-processing of gallery-dl image results.
+	ImageTransform *entity.ImageTransform
+	// Comments opts into collecting the source's comments alongside the
+	// media.
+	Comments *entity.CommentsOptions
+	// Transcription opts the job into post-download whisper.cpp
+	// transcription.
+	Transcription *entity.TranscriptionOptions
+	Translation   *entity.TranslationOptions
+	// Webhook overrides the server-wide default webhook URL for this job's
+	// status notifications.
+	Webhook string
+	// FaultMode requests a simulated downloader failure mode for resilience
+	// testing, see entity.Job.FaultMode. Ignored unless
+	// config.Job.FaultInjectionEnabled is set.
+	FaultMode string
+	// DownloadArchive, when set, dedupes this job against a yt-dlp
+	// download-archive file; see entity.Job.DownloadArchive.
+	DownloadArchive string
+	// RequestID correlates this job back to the API call that created it;
+	// see entity.Job.RequestID.
+	RequestID string
+	// Dedupe controls how a repeat request for the same URL+preset is
+	// handled; empty behaves like DedupeForceNew. Job.ID stays a random,
+	// unguessable UUID regardless of policy.
+	Dedupe DedupePolicy
+	// Labels are free-form key/value tags, see entity.Job.Labels.
+	Labels map[string]string
+	// MaxRateKbps caps this job's own download bandwidth, see
+	// entity.Job.MaxRateKbps.
+	MaxRateKbps int
+	// APIKey identifies the caller for applyKeyDefaults: any of Preset,
+	// Profile, Webhook or Labels left empty is filled in from this key's
+	// server-stored defaults, if any are configured; see SetKeyDefaults.
+	APIKey string
+	// Submitter identifies who's enqueueing this job (typically
+	// middleware.ClientIdentity), recorded on entity.Job.Submitter for the
+	// worker pool's fair-share scheduling.
+	Submitter string
+	// GroupID, TrackNumber and Album set the matching entity.Job fields,
+	// for a child job created by enqueueAlbum, enqueueRedditGallery or
+	// fillPlaylist; a caller enqueueing directly never needs to set these.
+	GroupID     *uuid.UUID
+	TrackNumber int
+	Album       string
+	// MaxItems caps how many items a playlist/gallery/album URL expands
+	// into, overriding the server-wide JobService.maxPlaylistItems when
+	// positive; see effectiveMaxItems.
+	MaxItems int
+}
+
+// DedupePolicy controls how EnqueueWithOptions handles a request for a
+// URL+preset it has already created a job for (see entity.DedupKey):
+//
+//   - DedupeReuse ("reuse") always returns the existing job.
+//   - DedupeForceNew ("force_new") always creates a new job, e.g. to force
+//     a re-download of content that may have changed since.
+//   - "reuse_if_fresh:<duration>" (e.g. "reuse_if_fresh:1h") returns the
+//     existing job only if it was created within duration of now,
+//     otherwise it behaves like DedupeForceNew.
+type DedupePolicy string
+
+const (
+	DedupeReuse    DedupePolicy = "reuse"
+	DedupeForceNew DedupePolicy = "force_new"
+)
+
+const reuseIfFreshPrefix = "reuse_if_fresh:"
+
+// dedupeTarget resolves policy against any existing job for url+preset,
+// returning the job EnqueueWithOptions should reuse, or nil to create a
+// new one.
+func (s *JobService) dedupeTarget(ctx context.Context, policy DedupePolicy, url, preset string) (*entity.Job, error) {
+	if policy == "" || policy == DedupeForceNew {
+		return nil, nil
+	}
+
+	existing, err := s.jobs.GetByDedupKey(ctx, entity.DedupKey(url, preset))
+	if err != nil {
+		return nil, nil
+	}
+
+	if policy == DedupeReuse {
+		return existing, nil
+	}
+
+	window, ok := strings.CutPrefix(string(policy), reuseIfFreshPrefix)
+	if !ok {
+		return nil, fmt.Errorf("unknown dedupe policy %q", policy)
+	}
+
+	freshFor, err := time.ParseDuration(window)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dedupe policy %q: %w", policy, err)
+	}
+
+	if time.Since(existing.CreatedAt) < freshFor {
+		return existing, nil
+	}
+
+	return nil, nil
+}
+
+// EnqueueWithOptions creates a new pending job from opts. When opts.Preset
+// is "auto", the URL is probed and a concrete preset picked by autoRules,
+// with the decision recorded on the job's event log. opts.APIKey's
+// server-stored defaults, if any, fill in whatever opts itself leaves
+// empty; see applyKeyDefaults.
+func (s *JobService) EnqueueWithOptions(ctx context.Context, opts EnqueueOptions) (*entity.Job, error) {
+	opts = s.applyKeyDefaults(ctx, opts)
+
+	if s.redditResolver != nil {
+		if result, err := s.redditResolver.Resolve(ctx, opts.URL); err == nil {
+			if result.Crosspost != "" {
+				opts.URL = result.Crosspost
+			} else if len(result.GalleryURLs) > 0 {
+				return s.enqueueRedditGallery(ctx, opts, result.GalleryURLs)
+			}
+		}
+	}
+
+	if opts.GroupID == nil && s.prober != nil && isMusicAlbumURL(opts.URL) {
+		return s.enqueueAlbum(ctx, opts)
+	}
+
+	resolved := opts.Preset
+
+	switch {
+	case opts.Preset == autoPreset:
+		resolved = s.resolveAutoPreset(ctx, opts.URL)
+	case opts.Preset == "":
+		resolved = s.defaultPresetForURL(opts.URL)
+	}
+
+	if existing, err := s.dedupeTarget(ctx, opts.Dedupe, opts.URL, resolved); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return existing, nil
+	}
+
+	job := entity.NewJob(opts.URL, resolved)
+	job.ClipStart = opts.ClipStart
+	job.ClipEnd = opts.ClipEnd
+	job.Profile = opts.Profile
+	job.GifOutput = opts.GifOutput
+	job.ImageTransform = opts.ImageTransform
+	job.Comments = opts.Comments
+	job.Transcription = opts.Transcription
+	job.Translation = opts.Translation
+	job.Webhook = opts.Webhook
+	job.FaultMode = opts.FaultMode
+	job.DownloadArchive = opts.DownloadArchive
+	job.RequestID = opts.RequestID
+	job.Labels = opts.Labels
+	job.MaxRateKbps = opts.MaxRateKbps
+	job.Submitter = opts.Submitter
+	job.GroupID = opts.GroupID
+	job.TrackNumber = opts.TrackNumber
+	job.Album = opts.Album
+
+	switch {
+	case opts.Preset == autoPreset:
+		job.AddEvent("preset_auto_selected", fmt.Sprintf("auto -> %s", resolved))
+	case opts.Preset == "":
+		job.AddEvent("preset_extractor_default", fmt.Sprintf("no preset given -> %s", resolved))
+	}
+
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("create job: %w", err)
+	}
+
+	if s.activeHours != nil && !s.activeHours.Contains(s.clock.Now()) {
+		return s.scheduleForActiveWindow(ctx, job)
+	}
+
+	job.SetStatus(entity.JobStatusQueued)
+	s.Notify(ctx, job, "queued")
+
+	if s.hub != nil {
+		s.hub.UpdateJobStatus(*job)
+	}
+
+	if s.pool != nil {
+		if err := s.pool.Submit(job); err != nil {
+			job.SetStatus(entity.JobStatusError)
+			job.SetError(err.Error())
+			_ = s.jobs.Update(ctx, job)
+
+			return job, err
+		}
+	}
+
+	return job, nil
+}
+
+// scheduleForActiveWindow holds job back as JobStatusScheduled until
+// s.activeHours's next window opens, instead of submitting it to the pool
+// right away.
+func (s *JobService) scheduleForActiveWindow(ctx context.Context, job *entity.Job) (*entity.Job, error) {
+	next := s.activeHours.NextStart(s.clock.Now())
+
+	job.SetStatus(entity.JobStatusScheduled)
+	job.ScheduledFor = &next
+	job.AddEvent("job_scheduled", fmt.Sprintf("deferred to active hours window, starting %s", next.Format(time.RFC3339)))
+
+	if err := s.jobs.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("schedule job: %w", err)
+	}
+
+	if s.hub != nil {
+		s.hub.UpdateJobStatus(*job)
+	}
+
+	go s.submitWhenActive(context.WithoutCancel(ctx), job, next)
+
+	return job, nil
+}
+
+// submitWhenActive waits until t (the active-hours window's next open
+// time) and then transitions job back to JobStatusQueued and submits it to
+// the pool. Run in its own goroutine by scheduleForActiveWindow.
+func (s *JobService) submitWhenActive(ctx context.Context, job *entity.Job, t time.Time) {
+	if wait := time.Until(t); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	if job.Status() == entity.JobStatusCancelled {
+		return
+	}
+
+	job.SetStatus(entity.JobStatusQueued)
+	job.ScheduledFor = nil
+	_ = s.jobs.Update(ctx, job)
+	s.Notify(ctx, job, "queued")
+
+	if s.hub != nil {
+		s.hub.UpdateJobStatus(*job)
+	}
+
+	if s.pool == nil {
+		return
+	}
+
+	if err := s.pool.Submit(job); err != nil {
+		job.SetStatus(entity.JobStatusError)
+		job.SetError(err.Error())
+		_ = s.jobs.Update(ctx, job)
+	}
+}
+
+// EnqueuePlaylist probes url with the configured prober and expands it into
+// one child job per discovered item, grouped under an entity.JobGroup so
+// the parent URL's overall progress can be tracked via JobGroup.Progress,
+// the same way ArchiveService tracks an account archive. Unlike Enqueue,
+// the URL itself never becomes a job. downloadArchive, if set, is passed
+// through to every child job so a re-run only fetches new items; see
+// SchedulerService. If empty and playlistArchiveDir is configured, one is
+// derived from url so that repeat submissions of the same playlist/channel
+// also only fetch items not already downloaded. maxItems caps how many
+// items are enqueued, overriding the server-wide default when positive;
+// see effectiveMaxItems and entity.JobGroup.Truncated.
+func (s *JobService) EnqueuePlaylist(ctx context.Context, url, preset, downloadArchive string, maxItems int) (*entity.JobGroup, error) {
+	if downloadArchive == "" && s.playlistArchiveDir != "" {
+		downloadArchive = s.playlistArchiveFile(url)
+	}
+
+	cap := s.effectiveMaxItems(maxItems)
+
+	items, err := s.prober.Probe(ctx, url, cap)
+	if err != nil {
+		return nil, fmt.Errorf("probe playlist: %w", err)
+	}
+
+	truncated := cap > 0 && len(items) >= cap
+	if cap > 0 && len(items) > cap {
+		items = items[:cap]
+	}
+
+	group := entity.NewJobGroup(url, preset)
+	group.Total = len(items)
+	group.Truncated = truncated
+
+	if err := s.groups.Create(ctx, group); err != nil {
+		return nil, fmt.Errorf("create job group: %w", err)
+	}
+
+	go s.fillPlaylist(context.WithoutCancel(ctx), group, items, downloadArchive)
+
+	return group, nil
+}
+
+// playlistArchiveFile returns the path of the yt-dlp download-archive file
+// for a playlist/channel URL enqueued via EnqueuePlaylist, deterministically
+// derived from url so repeat submissions of the same source reuse it.
+func (s *JobService) playlistArchiveFile(url string) string {
+	sum := sha256.Sum256([]byte(url))
+
+	return filepath.Join(s.playlistArchiveDir, hex.EncodeToString(sum[:])+".txt")
+}
+
+// enqueueRedditGallery enqueues one child job per image in a Reddit
+// gallery post, grouped under an entity.JobGroup the same way
+// EnqueuePlaylist groups a playlist's items, and returns the first child
+// job created so callers expecting a single *entity.Job from
+// EnqueueWithOptions still get one to report back; the rest are reachable
+// through the group. Unlike EnqueuePlaylist, this runs synchronously: a
+// gallery is a handful of images, not a channel's worth of videos, so
+// there's no need to return before it's done.
+func (s *JobService) enqueueRedditGallery(ctx context.Context, opts EnqueueOptions, urls []string) (*entity.Job, error) {
+	truncated := false
+	if cap := s.effectiveMaxItems(opts.MaxItems); cap > 0 && len(urls) > cap {
+		urls = urls[:cap]
+		truncated = true
+	}
+
+	group := entity.NewJobGroup(opts.URL, opts.Preset)
+	group.Total = len(urls)
+	group.Truncated = truncated
+
+	if err := s.groups.Create(ctx, group); err != nil {
+		return nil, fmt.Errorf("create job group: %w", err)
+	}
+
+	var first *entity.Job
+
+	for i, url := range urls {
+		childOpts := opts
+		childOpts.URL = url
+		childOpts.GroupID = &group.ID
+
+		job, err := s.EnqueueWithOptions(ctx, childOpts)
+		if err != nil {
+			continue
+		}
+
+		job.AddEvent("reddit_gallery_item", fmt.Sprintf("item %d/%d of gallery %s", i+1, len(urls), group.ID))
+		_ = s.jobs.Update(ctx, job)
+
+		group.JobIDs = append(group.JobIDs, job.ID)
+		group.Items[fmt.Sprintf("%d", i)] = job.ID
+
+		if first == nil {
+			first = job
+		}
+	}
+
+	if truncated && first != nil {
+		first.AddEvent("gallery_truncated", fmt.Sprintf("gallery capped at %d items; see JobGroup.Truncated", len(urls)))
+		_ = s.jobs.Update(ctx, first)
+	}
+
+	if err := s.groups.Update(ctx, group); err != nil {
+		return first, fmt.Errorf("update job group: %w", err)
+	}
+
+	if first == nil {
+		return nil, fmt.Errorf("reddit gallery %s: no items could be enqueued", opts.URL)
+	}
+
+	return first, nil
+}
+
+// isMusicAlbumURL reports whether url points at a Bandcamp album or
+// SoundCloud set, the two sources enqueueAlbum knows how to expand into
+// numbered tracks with an m3u playlist.
+func isMusicAlbumURL(rawURL string) bool {
+	domain := worker.Domain(rawURL)
+
+	switch {
+	case domain == "bandcamp.com" || strings.HasSuffix(domain, ".bandcamp.com"):
+		return strings.Contains(rawURL, "/album/")
+	case domain == "soundcloud.com":
+		return strings.Contains(rawURL, "/sets/")
+	default:
+		return false
+	}
+}
+
+// albumTitle returns url's title via the configured metadata prober, or ""
+// if none is configured or probing fails; used to tag enqueueAlbum's
+// tracks for the m3u playlist's benefit.
+func (s *JobService) albumTitle(ctx context.Context, url string) string {
+	if s.metadataProber == nil {
+		return ""
+	}
+
+	md, err := s.metadataProber.Metadata(ctx, url)
+	if err != nil {
+		return ""
+	}
+
+	return md.Title
+}
+
+// enqueueAlbum probes opts.URL (a Bandcamp album or SoundCloud set, see
+// isMusicAlbumURL) and enqueues one child job per track, grouped under an
+// entity.JobGroup the same way enqueueRedditGallery groups a gallery's
+// images. Each track is tagged with its position and the album's title
+// (see entity.Job.TrackNumber/Album) so the runner can embed them and,
+// once every track reaches a terminal status, assemble an m3u playlist;
+// see YtDlpRunner.checkGroupComplete. Runs synchronously like
+// enqueueRedditGallery: an album is at most a few dozen tracks.
+func (s *JobService) enqueueAlbum(ctx context.Context, opts EnqueueOptions) (*entity.Job, error) {
+	cap := s.effectiveMaxItems(opts.MaxItems)
+
+	items, err := s.prober.Probe(ctx, opts.URL, cap)
+	if err != nil {
+		return nil, fmt.Errorf("probe album: %w", err)
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("album %s: no tracks found", opts.URL)
+	}
+
+	truncated := cap > 0 && len(items) >= cap
+	if cap > 0 && len(items) > cap {
+		items = items[:cap]
+	}
+
+	album := s.albumTitle(ctx, opts.URL)
+
+	group := entity.NewJobGroup(opts.URL, opts.Preset)
+	group.Total = len(items)
+	group.GenerateM3U = true
+	group.Truncated = truncated
+
+	if err := s.groups.Create(ctx, group); err != nil {
+		return nil, fmt.Errorf("create job group: %w", err)
+	}
+
+	var first *entity.Job
+
+	for i, item := range items {
+		childOpts := opts
+		childOpts.URL = item.URL
+		childOpts.TrackNumber = i + 1
+		childOpts.Album = album
+		childOpts.GroupID = &group.ID
+
+		job, err := s.EnqueueWithOptions(ctx, childOpts)
+		if err != nil {
+			continue
+		}
+
+		job.AddEvent("album_track", fmt.Sprintf("track %d/%d of album %s", i+1, len(items), group.ID))
+		_ = s.jobs.Update(ctx, job)
+
+		group.JobIDs = append(group.JobIDs, job.ID)
+		group.Items[item.ID] = job.ID
+
+		if first == nil {
+			first = job
+		}
+	}
+
+	if truncated && first != nil {
+		first.AddEvent("album_truncated", fmt.Sprintf("album capped at %d tracks; see JobGroup.Truncated", len(items)))
+		_ = s.jobs.Update(ctx, first)
+	}
+
+	if err := s.groups.Update(ctx, group); err != nil {
+		return first, fmt.Errorf("update job group: %w", err)
+	}
+
+	if first == nil {
+		return nil, fmt.Errorf("album %s: no tracks could be enqueued", opts.URL)
+	}
+
+	return first, nil
+}
+
+// fillPlaylist enqueues a child job per item, recording each under group.
+func (s *JobService) fillPlaylist(ctx context.Context, group *entity.JobGroup, items []crawler.Item, downloadArchive string) {
+	for _, item := range items {
+		opts := EnqueueOptions{URL: item.URL, Preset: group.Preset, DownloadArchive: downloadArchive, GroupID: &group.ID}
+
+		job, err := s.EnqueueWithOptions(ctx, opts)
+		if err == nil {
+			group.JobIDs = append(group.JobIDs, job.ID)
+			group.Items[item.ID] = job.ID
+		}
+
+		_ = s.groups.Update(ctx, group)
+	}
+}
+
+// DryRunResult previews what EnqueueWithOptions would do, without actually
+// creating or running a job.
+type DryRunResult struct {
+	Preset      string
+	CommandLine string
+}
+
+// DryRun resolves opts the same way EnqueueWithOptions does (including
+// "auto" preset selection) and returns the chosen preset and the command
+// line that would be executed, without creating or running anything.
+// Invaluable for debugging downloader/proxy/preset configuration.
+func (s *JobService) DryRun(ctx context.Context, opts EnqueueOptions) (*DryRunResult, error) {
+	opts = s.applyKeyDefaults(ctx, opts)
+	resolved := opts.Preset
+
+	switch {
+	case opts.Preset == autoPreset:
+		resolved = s.resolveAutoPreset(ctx, opts.URL)
+	case opts.Preset == "":
+		resolved = s.defaultPresetForURL(opts.URL)
+	}
+
+	job := entity.NewJob(opts.URL, resolved)
+	job.Profile = opts.Profile
+
+	result := &DryRunResult{Preset: resolved}
+
+	if s.runner != nil {
+		result.CommandLine = s.runner.Preview(job)
+	}
+
+	return result, nil
+}
+
+// ProbeMetadata returns url's title, duration, available formats and
+// estimated size without creating a job or downloading anything; it is the
+// handler for POST /v1/jobs/probe.
+func (s *JobService) ProbeMetadata(ctx context.Context, url string) (*crawler.Metadata, error) {
+	if s.metadataProber == nil {
+		return nil, fmt.Errorf("probe: no metadata prober configured")
+	}
+
+	meta, err := s.metadataProber.Metadata(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("probe metadata: %w", err)
+	}
+
+	return meta, nil
+}
+
+// resolveAutoPreset probes url and picks a preset via autoRules, falling
+// back to "original" when probing fails or isn't configured.
+func (s *JobService) resolveAutoPreset(ctx context.Context, url string) string {
+	if s.prober == nil {
+		return "original"
+	}
+
+	items, err := s.prober.Probe(ctx, url, 0)
+	if err != nil || len(items) == 0 {
+		return "original"
+	}
+
+	return presetpkg.SelectAuto(items[0], s.autoRules)
+}
+
+// Update persists changes made to job and broadcasts the new state to any
+// Hub subscribers watching it.
+func (s *JobService) Update(ctx context.Context, job *entity.Job) error {
+	if err := s.jobs.Update(ctx, job); err != nil {
+		return fmt.Errorf("update job: %w", err)
+	}
+
+	if s.hub != nil {
+		s.hub.UpdateJobStatus(*job)
+	}
+
+	return nil
+}
+
+// registerCancel/unregisterCancel let YtDlpRunner.Run expose a per-job
+// cancellation scope to Cancel without the two depending on each other's
+// internals; see cancelFns.
+func (s *JobService) registerCancel(id uuid.UUID, cancel context.CancelFunc) {
+	s.cancelFnsMu.Lock()
+	defer s.cancelFnsMu.Unlock()
+
+	s.cancelFns[id] = cancel
+}
+
+func (s *JobService) unregisterCancel(id uuid.UUID) {
+	s.cancelFnsMu.Lock()
+	defer s.cancelFnsMu.Unlock()
+
+	delete(s.cancelFns, id)
+}
+
+// Cancel marks job as cancelled and, if it's currently running, cancels
+// its per-job context: execenv.Command runs every subprocess via
+// exec.CommandContext, so this kills the yt-dlp/gallery-dl process
+// immediately rather than waiting for it to notice. The job's on-disk
+// scratch directory is removed by YtDlpRunner.Run's own cleanup once the
+// killed process exits.
+func (s *JobService) Cancel(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job.SetStatus(entity.JobStatusCancelled)
+	job.AddEvent("job_cancelled", "cancellation requested")
+
+	if err := s.Update(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.cancelFnsMu.Lock()
+	cancel := s.cancelFns[id]
+	s.cancelFnsMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	return job, nil
+}
+
+// Pause suspends a running job's downloader process (SIGSTOP), leaving its
+// partial output in place so Resume can continue it. It fails if the job
+// isn't currently running.
+func (s *JobService) Pause(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status() != entity.JobStatusRunning {
+		return nil, fmt.Errorf("job %s is %s, not running", id, job.Status())
+	}
+
+	if s.runner == nil {
+		return nil, fmt.Errorf("pause: no runner configured")
+	}
+
+	if err := s.runner.Pause(id); err != nil {
+		return nil, fmt.Errorf("pause job: %w", err)
+	}
+
+	job.SetStatus(entity.JobStatusPaused)
+	job.AddEvent("job_paused", "pause requested")
+
+	if err := s.Update(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Resume resumes a paused job's downloader process (SIGCONT). It fails if
+// the job isn't currently paused.
+func (s *JobService) Resume(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status() != entity.JobStatusPaused {
+		return nil, fmt.Errorf("job %s is %s, not paused", id, job.Status())
+	}
+
+	if s.runner == nil {
+		return nil, fmt.Errorf("resume: no runner configured")
+	}
+
+	if err := s.runner.Resume(id); err != nil {
+		return nil, fmt.Errorf("resume job: %w", err)
+	}
+
+	job.SetStatus(entity.JobStatusRunning)
+	job.AddEvent("job_resumed", "resume requested")
+
+	if err := s.Update(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Get returns a job by ID.
+func (s *JobService) Get(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	job, err := s.jobs.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// List returns jobs, newest first. label, if non-empty, filters to jobs
+// tagged "key:value" (see entity.Job.Labels); it must be in "key:value"
+// form.
+func (s *JobService) List(ctx context.Context, label string) ([]*entity.Job, error) {
+	if label == "" {
+		jobs, err := s.jobs.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list jobs: %w", err)
+		}
+
+		return jobs, nil
+	}
+
+	key, value, ok := strings.Cut(label, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid label filter %q, expected key:value", label)
+	}
+
+	jobs, err := s.jobs.ListByLabel(ctx, key, value)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs by label: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// terminalJobStatuses are the statuses deleteExpiredJobs treats as
+// finished, and so eligible for deletion once past their TTL.
+var terminalJobStatuses = map[entity.JobStatus]bool{
+	entity.JobStatusDone:      true,
+	entity.JobStatusError:     true,
+	entity.JobStatusCancelled: true,
+}
+
+// deleteExpiredJobs removes terminal jobs created before cutoff. It keeps
+// job metadata (status, error, event log) around for
+// config.Retention.MetadataTTL after completion, the same way
+// PublicationService.SweepRetention keeps publication metadata, even
+// though the underlying downloaded files are cleaned up separately, and
+// much sooner, via config.Retention.FileTTL; see PublicationService.SweepRetention.
+func (s *JobService) deleteExpiredJobs(ctx context.Context, cutoff time.Time) error {
+	jobs, err := s.jobs.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		if !terminalJobStatuses[job.Status()] || job.CreatedAt.After(cutoff) {
+			continue
+		}
+
+		if err := s.jobs.Delete(ctx, job.ID); err != nil {
+			return fmt.Errorf("delete job %s: %w", job.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// durationEWMAWeight controls how quickly durationStats.average reacts to a
+// newly finished job's duration; see throughputEWMAWeight. A plain running
+// mean weighs a stale sample from before a connection got throttled exactly
+// as heavily as the most recent one, which is what made EstimateWait swing
+// wildly; EWMA lets recent attempts dominate instead.
+const durationEWMAWeight = 0.3
+
+// WaitConfidence grades how much an EstimateWait estimate should be
+// trusted, based on how many completed jobs it's built from.
+type WaitConfidence string
+
+const (
+	// WaitConfidenceNone means no estimate could be made at all (no history
+	// for this domain or any other).
+	WaitConfidenceNone WaitConfidence = "none"
+	// WaitConfidenceLow means the estimate is backed by only a couple of
+	// samples and may be unreliable.
+	WaitConfidenceLow WaitConfidence = "low"
+	// WaitConfidenceMedium means the estimate has a modest sample size.
+	WaitConfidenceMedium WaitConfidence = "medium"
+	// WaitConfidenceHigh means the estimate is backed by a solid sample
+	// size for this specific domain.
+	WaitConfidenceHigh WaitConfidence = "high"
+)
+
+// durationStats accumulates an exponentially-weighted average job duration
+// per source domain (e.g. youtube.com, instagram.com), used to estimate
+// queue wait time for newly enqueued jobs; see JobService.EstimateWait. It
+// is not persisted: estimates reset to the global fallback average on
+// restart.
+type durationStats struct {
+	mu   sync.Mutex
+	avg  map[string]time.Duration
+	n    map[string]int64
+	sum  time.Duration // across every domain, for the fallback average
+	nAll int64
+}
+
+func newDurationStats() *durationStats {
+	return &durationStats{
+		avg: make(map[string]time.Duration),
+		n:   make(map[string]int64),
+	}
+}
+
+// record folds d into domain's EWMA average (and the global fallback
+// average's plain running mean, which has no single domain's recent
+// throttling to react to).
+func (s *durationStats) record(domain string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.n[domain]++
+
+	if avg, ok := s.avg[domain]; ok {
+		s.avg[domain] = avg + time.Duration(durationEWMAWeight*float64(d-avg))
+	} else {
+		s.avg[domain] = d
+	}
+
+	s.nAll++
+	s.sum += d
+}
+
+// average returns the EWMA average job duration for domain and a
+// confidence grading based on how many samples it's seen, falling back to
+// the average across all domains (at WaitConfidenceLow, since it's not
+// specific to this domain) if domain has no history of its own yet, or 0 /
+// WaitConfidenceNone if there's no history at all.
+func (s *durationStats) average(domain string) (time.Duration, WaitConfidence) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if avg, ok := s.avg[domain]; ok {
+		return avg, confidenceFor(s.n[domain])
+	}
+
+	if s.nAll == 0 {
+		return 0, WaitConfidenceNone
+	}
+
+	return s.sum / time.Duration(s.nAll), WaitConfidenceLow
+}
+
+// confidenceFor grades a sample count into a WaitConfidence.
+func confidenceFor(n int64) WaitConfidence {
+	switch {
+	case n >= 10:
+		return WaitConfidenceHigh
+	case n >= 3:
+		return WaitConfidenceMedium
+	default:
+		return WaitConfidenceLow
+	}
+}
+
+// throughputEWMAWeight controls how quickly throughputStats.current reacts
+// to a new sample; 0.2 means each attempt's own bytes/sec moves the
+// estimate a fifth of the way toward it, smoothing out single-job bursts.
+const throughputEWMAWeight = 0.2
+
+// throughputStats estimates download throughput from finished attempts,
+// exposed via the admin queue stats endpoint. It keeps no history, just an
+// exponentially-weighted average of each attempt's own bytes/sec, so it
+// does not add up the rates of jobs running at the same time.
+type throughputStats struct {
+	mu  sync.Mutex
+	bps float64
+}
+
+func newThroughputStats() *throughputStats {
+	return &throughputStats{}
+}
+
+// record folds one attempt's average throughput (bytes over d) into the
+// running estimate. Attempts with no measurable bytes or duration are
+// ignored rather than dragging the estimate toward zero.
+func (s *throughputStats) record(bytes int64, d time.Duration) {
+	if bytes <= 0 || d <= 0 {
+		return
+	}
+
+	sample := float64(bytes) / d.Seconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bps == 0 {
+		s.bps = sample
+		return
+	}
+
+	s.bps += throughputEWMAWeight * (sample - s.bps)
+}
+
+// current returns the current estimated aggregate throughput in bytes/sec,
+// 0 if no attempt has completed yet.
+func (s *throughputStats) current() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bps
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/repository"
+)
+
+// KeyDefaultsService owns each API key's server-stored default enqueue
+// options, see entity.KeyDefaults and PUT /v1/me/defaults.
+type KeyDefaultsService struct {
+	defaults repository.KeyDefaultsRepository
+}
+
+// NewKeyDefaultsService builds a KeyDefaultsService.
+func NewKeyDefaultsService(defaults repository.KeyDefaultsRepository) *KeyDefaultsService {
+	return &KeyDefaultsService{defaults: defaults}
+}
+
+// Get returns key's stored defaults.
+func (s *KeyDefaultsService) Get(ctx context.Context, key string) (*entity.KeyDefaults, error) {
+	defaults, err := s.defaults.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("get key defaults: %w", err)
+	}
+
+	return defaults, nil
+}
+
+// Set stores defaults, overwriting whatever key previously had set.
+func (s *KeyDefaultsService) Set(ctx context.Context, defaults *entity.KeyDefaults) error {
+	if err := s.defaults.Set(ctx, defaults); err != nil {
+		return fmt.Errorf("set key defaults: %w", err)
+	}
+
+	return nil
+}
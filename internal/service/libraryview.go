@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/worker"
+)
+
+// LibraryViewService maintains a parallel directory tree under Dir of
+// hardlinks (falling back to symlinks when the two trees don't share a
+// filesystem) into the canonical files jobs produce under OutputDir,
+// organized by <platform>/<relative output path>, so a media server gets
+// a browsable library layout without duplicating storage. The relative
+// path under a platform preserves whatever subdirectory structure
+// config.Job.DirectoryLayouts resolved for that job, e.g.
+// youtube.com/<uploader>/<year>/<file>. It doesn't hook into job
+// completion or purge directly; instead Sync periodically reconciles the
+// tree against the job records that currently exist, which keeps it
+// correct even across jobs deleted while the server was down.
+type LibraryViewService struct {
+	dir    string
+	outDir string
+	jobs   *JobService
+}
+
+// NewLibraryViewService builds a LibraryViewService. dir is the hardlink
+// farm's root; empty disables it entirely (Sync becomes a no-op). outDir
+// is the downloads root jobs' entity.Job.OutputPath is resolved relative
+// to.
+func NewLibraryViewService(dir, outDir string, jobs *JobService) *LibraryViewService {
+	return &LibraryViewService{dir: dir, outDir: outDir, jobs: jobs}
+}
+
+// Sync reconciles the library view tree against every job that currently
+// has an entity.Job.OutputPath recorded: missing links are created, and
+// any file under Dir that no longer corresponds to one is removed. A
+// no-op if the service has no Dir configured.
+func (s *LibraryViewService) Sync(ctx context.Context) error {
+	if s.dir == "" {
+		return nil
+	}
+
+	jobs, err := s.jobs.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("list jobs: %w", err)
+	}
+
+	wanted := make(map[string]struct{}, len(jobs))
+
+	for _, job := range jobs {
+		if job.OutputPath == "" {
+			continue
+		}
+
+		linkPath, ok := s.linkPath(job)
+		if !ok {
+			continue
+		}
+
+		wanted[linkPath] = struct{}{}
+
+		if _, err := os.Lstat(linkPath); err == nil {
+			continue
+		}
+
+		if err := s.link(job.OutputPath, linkPath); err != nil {
+			return fmt.Errorf("link %s: %w", linkPath, err)
+		}
+	}
+
+	return s.pruneStale(wanted)
+}
+
+// linkPath returns where job's canonical file should be linked under Dir,
+// and false if job.OutputPath isn't actually under outDir (shouldn't
+// happen, but a job record from a misconfigured OutputDir shouldn't wreck
+// the rest of the tree).
+func (s *LibraryViewService) linkPath(job *entity.Job) (string, bool) {
+	rel, err := filepath.Rel(s.outDir, job.OutputPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+
+	return filepath.Join(s.dir, worker.Domain(job.URL), rel), true
+}
+
+// link creates linkPath pointing at canonicalPath, preferring a hardlink
+// (so the library view costs no extra disk space) and falling back to a
+// symlink when the two paths don't share a filesystem.
+func (s *LibraryViewService) link(canonicalPath, linkPath string) error {
+	if err := os.MkdirAll(filepath.Dir(linkPath), 0o755); err != nil {
+		return err
+	}
+
+	if err := os.Link(canonicalPath, linkPath); err != nil {
+		return os.Symlink(canonicalPath, linkPath)
+	}
+
+	return nil
+}
+
+// pruneStale removes every file under Dir not in wanted, e.g. because its
+// job was purged since the last Sync.
+func (s *LibraryViewService) pruneStale(wanted map[string]struct{}) error {
+	return filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		if _, ok := wanted[path]; !ok {
+			_ = os.Remove(path)
+		}
+
+		return nil
+	})
+}
+
+// RunSyncLoop calls Sync on the given interval until ctx is cancelled.
+func (s *LibraryViewService) RunSyncLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.Sync(ctx)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/config"
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/enrich"
+	"github.com/evan-sm/daunrodo/internal/execenv"
+	"github.com/evan-sm/daunrodo/internal/repository"
+	"github.com/evan-sm/daunrodo/internal/worker"
+	"github.com/evan-sm/daunrodo/pkg/clock"
+)
+
+// PublicationService owns publication lifecycle, including the retention
+// sweep that keeps history around longer than the files it refers to.
+type PublicationService struct {
+	pubs      repository.PublicationRepository
+	jobs      *JobService
+	cfg       config.Retention
+	clock     clock.Clock
+	log       *slog.Logger
+	enricher  *enrich.Service
+	thumbsCfg config.Thumbnail
+}
+
+// NewPublicationService builds a PublicationService.
+func NewPublicationService(pubs repository.PublicationRepository, jobs *JobService, cfg config.Retention, clk clock.Clock, log *slog.Logger) *PublicationService {
+	return &PublicationService{pubs: pubs, jobs: jobs, cfg: cfg, clock: clk, log: log}
+}
+
+// SetEnricher wires in the optional platform-API metadata enrichment
+// service; a nil enricher (the default) leaves EnrichMetadata a no-op. See
+// config.PlatformAPIs.
+func (s *PublicationService) SetEnricher(enricher *enrich.Service) {
+	s.enricher = enricher
+}
+
+// SetThumbnailConfig wires in the thumbnail cache directory and ffmpeg
+// path used by EnsureThumbnail.
+func (s *PublicationService) SetThumbnailConfig(cfg config.Thumbnail) {
+	s.thumbsCfg = cfg
+}
+
+// Get returns a publication by ID, expired or not.
+func (s *PublicationService) Get(ctx context.Context, id uuid.UUID) (*entity.Publication, error) {
+	pub, err := s.pubs.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get publication: %w", err)
+	}
+
+	return pub, nil
+}
+
+// GetByJob returns the publication produced by jobID, if any.
+func (s *PublicationService) GetByJob(ctx context.Context, jobID uuid.UUID) (*entity.Publication, error) {
+	pub, err := s.pubs.GetByJobID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("get publication by job: %w", err)
+	}
+
+	return pub, nil
+}
+
+// ListByJob returns every publication produced by jobID, newest first. A
+// job produces at most one today (see repository.PublicationRepository's
+// 1:1 GetByJobID), so the result has at most one element; it's a list
+// rather than GetByJob's single record for callers building a library view
+// that doesn't want a 404 special case for "this job hasn't finished yet".
+func (s *PublicationService) ListByJob(ctx context.Context, jobID uuid.UUID) ([]*entity.Publication, error) {
+	pub, err := s.pubs.GetByJobID(ctx, jobID)
+	if err != nil {
+		return nil, nil
+	}
+
+	return []*entity.Publication{pub}, nil
+}
+
+// PublicationFilter selects which publications List returns; a zero-valued
+// field leaves that dimension unfiltered.
+type PublicationFilter struct {
+	// Platform matches pub.SourceURL's host, e.g. "youtube.com"; see
+	// worker.Domain.
+	Platform string
+	// Author matches Publication.Author as a case-insensitive substring.
+	Author string
+	// Type matches the downloaded file's coarse media type ("video",
+	// "audio" or "image", guessed from its extension); see mediaType.
+	Type string
+	// TitleContains matches Publication.Title as a case-insensitive
+	// substring.
+	TitleContains string
+	// CreatedAfter/CreatedBefore, if non-zero, bound Publication.CreatedAt.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// List returns every publication matching filter, newest first.
+func (s *PublicationService) List(ctx context.Context, filter PublicationFilter) ([]*entity.Publication, error) {
+	pubs, err := s.pubs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list publications: %w", err)
+	}
+
+	matched := make([]*entity.Publication, 0, len(pubs))
+
+	for _, pub := range pubs {
+		if filter.Platform != "" && worker.Domain(pub.SourceURL) != filter.Platform {
+			continue
+		}
+
+		if filter.Author != "" && !strings.Contains(strings.ToLower(pub.Author), strings.ToLower(filter.Author)) {
+			continue
+		}
+
+		if filter.Type != "" && mediaType(pub) != filter.Type {
+			continue
+		}
+
+		if filter.TitleContains != "" && !strings.Contains(strings.ToLower(pub.Title), strings.ToLower(filter.TitleContains)) {
+			continue
+		}
+
+		if !filter.CreatedAfter.IsZero() && pub.CreatedAt.Before(filter.CreatedAfter) {
+			continue
+		}
+
+		if !filter.CreatedBefore.IsZero() && pub.CreatedAt.After(filter.CreatedBefore) {
+			continue
+		}
+
+		matched = append(matched, pub)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	return matched, nil
+}
+
+// mediaType best-effort classifies pub's downloaded file as "video",
+// "audio" or "image" from its extension, returning "" for anything else
+// (including a publication with no file, e.g. one the retention sweep has
+// already expired).
+func mediaType(pub *entity.Publication) string {
+	ct := mime.TypeByExtension(filepath.Ext(pub.FilePath))
+
+	typ, _, _ := strings.Cut(ct, "/")
+
+	return typ
+}
+
+// GetByFileSHA256 returns the publication whose file hashes to sha256.
+func (s *PublicationService) GetByFileSHA256(ctx context.Context, sha256 string) (*entity.Publication, error) {
+	pub, err := s.pubs.GetByFileSHA256(ctx, sha256)
+	if err != nil {
+		return nil, fmt.Errorf("get publication by file hash: %w", err)
+	}
+
+	return pub, nil
+}
+
+// EnsureFileHash returns pub's content hash, computing and persisting it on
+// first use since finished artifacts never change. It's used to serve files
+// under the content-addressed /v1/files/{sha256} route, which can be fronted
+// by a CDN with an immutable, far-future Cache-Control.
+func (s *PublicationService) EnsureFileHash(ctx context.Context, pub *entity.Publication) (string, error) {
+	if pub.FileSHA256 != "" {
+		return pub.FileSHA256, nil
+	}
+
+	f, err := os.Open(pub.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hash file: %w", err)
+	}
+
+	pub.FileSHA256 = hex.EncodeToString(h.Sum(nil))
+
+	if err := s.pubs.Update(ctx, pub); err != nil {
+		return "", fmt.Errorf("persist file hash: %w", err)
+	}
+
+	return pub.FileSHA256, nil
+}
+
+// EnsureThumbnail returns a cover-frame image path for pub, generating one
+// via ffmpeg into config.Thumbnail.CacheDir on first call if pub has no
+// ThumbnailPath yet (yt-dlp didn't write one alongside the download), and
+// regenerating if the previously recorded path no longer exists (e.g. the
+// cache directory was cleared).
+func (s *PublicationService) EnsureThumbnail(ctx context.Context, pub *entity.Publication) (string, error) {
+	if pub.ThumbnailPath != "" {
+		if _, err := os.Stat(pub.ThumbnailPath); err == nil {
+			return pub.ThumbnailPath, nil
+		}
+	}
+
+	if pub.FilePath == "" {
+		return "", fmt.Errorf("generate thumbnail: publication %s has no file to grab a frame from", pub.UUID)
+	}
+
+	if err := os.MkdirAll(s.thumbsCfg.CacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("generate thumbnail: create cache dir: %w", err)
+	}
+
+	out := filepath.Join(s.thumbsCfg.CacheDir, pub.UUID.String()+".jpg")
+
+	ffmpeg := s.thumbsCfg.FfmpegPath
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+
+	cmd := execenv.Command(ctx, s.thumbsCfg.CacheDir, ffmpeg,
+		"-y", "-ss", "1", "-i", pub.FilePath, "-frames:v", "1", "-q:v", "2", out)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("generate thumbnail: ffmpeg: %w: %s", err, output)
+	}
+
+	pub.ThumbnailPath = out
+
+	if err := s.pubs.Update(ctx, pub); err != nil {
+		return "", fmt.Errorf("generate thumbnail: persist path: %w", err)
+	}
+
+	return out, nil
+}
+
+// RecordAccess bumps pub's DownloadCount and sets LastAccessedAt to now,
+// called each time its file is actually served (not on metadata reads).
+// Failures to persist are logged rather than returned, since a missed
+// access-time update shouldn't fail the download itself.
+func (s *PublicationService) RecordAccess(ctx context.Context, pub *entity.Publication) {
+	now := s.clock.Now()
+	pub.DownloadCount++
+	pub.LastAccessedAt = &now
+
+	if err := s.pubs.Update(ctx, pub); err != nil {
+		s.log.Warn("record publication access", "publication", pub.UUID, "error", err)
+	}
+}
+
+// EnrichMetadata best-effort augments pub's Metadata with canonical fields
+// from an official platform API (exact publish date, license, content
+// category), if one is configured and recognizes pub's source platform;
+// see config.PlatformAPIs and internal/enrich. It's a no-op if no
+// enricher is configured, the platform isn't supported or its
+// credentials aren't set, or pub was already enriched. Like RecordAccess,
+// failures are logged rather than returned since failing to enrich
+// shouldn't fail the metadata read that triggered it.
+func (s *PublicationService) EnrichMetadata(ctx context.Context, pub *entity.Publication) {
+	if s.enricher == nil || (pub.Metadata != nil && pub.Metadata.Source != "") {
+		return
+	}
+
+	md, err := s.enricher.Enrich(ctx, pub.SourceURL)
+	if err != nil {
+		if !errors.Is(err, enrich.ErrUnsupported) {
+			s.log.Warn("metadata enrichment failed", "publication", pub.UUID, "error", err)
+		}
+
+		return
+	}
+
+	if pub.Metadata == nil {
+		pub.Metadata = &entity.Metadata{}
+	}
+
+	pub.Metadata.Source = md.Source
+	pub.Metadata.PublishedAt = md.PublishedAt
+	pub.Metadata.License = md.License
+	pub.Metadata.Category = md.Category
+
+	if err := s.pubs.Update(ctx, pub); err != nil {
+		s.log.Warn("persist enriched metadata", "publication", pub.UUID, "error", err)
+	}
+}
+
+// PublicationStats aggregates download activity across every publication,
+// for the admin stats endpoint.
+type PublicationStats struct {
+	TotalDownloads    int `json:"total_downloads"`
+	NeverAccessed     int `json:"never_accessed"`
+	PublicationsCount int `json:"publications_count"`
+}
+
+// Stats aggregates DownloadCount/LastAccessedAt across every publication.
+func (s *PublicationService) Stats(ctx context.Context) (*PublicationStats, error) {
+	pubs, err := s.pubs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list publications: %w", err)
+	}
+
+	stats := &PublicationStats{PublicationsCount: len(pubs)}
+
+	for _, pub := range pubs {
+		stats.TotalDownloads += pub.DownloadCount
+
+		if pub.LastAccessedAt == nil {
+			stats.NeverAccessed++
+		}
+	}
+
+	return stats, nil
+}
+
+// ErrNotExpired is returned by Refetch when the publication's file is still
+// present, so re-downloading it would be redundant.
+var ErrNotExpired = errors.New("publication is not expired")
+
+// Refetch re-enqueues a job for an expired publication's original source URL
+// and preset, and records the new publication as a refetch of it.
+func (s *PublicationService) Refetch(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	pub, err := s.pubs.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get publication: %w", err)
+	}
+
+	if pub.Status != entity.PublicationStatusExpired {
+		return nil, ErrNotExpired
+	}
+
+	job, err := s.jobs.Enqueue(ctx, pub.SourceURL, pub.Preset)
+	if err != nil {
+		return nil, fmt.Errorf("enqueue refetch job: %w", err)
+	}
+
+	job.RefetchOf = &pub.UUID
+
+	if err := s.jobs.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("link refetch job: %w", err)
+	}
+
+	return job, nil
+}
+
+// SweepRetention removes files past FileTTL (marking their publications
+// expired) and fully deletes publication records past MetadataTTL, along
+// with any terminal job record that's also past MetadataTTL (see
+// JobService.deleteExpiredJobs): job status/error/event history is kept
+// around for the full MetadataTTL window, independent of how much sooner
+// the heavier downloaded file itself gets cleaned up via FileTTL. It is
+// meant to be called periodically, e.g. from a ticker in cmd/daunrodo.
+func (s *PublicationService) SweepRetention(ctx context.Context) error {
+	now := s.clock.Now()
+
+	expiring, err := s.pubs.ListExpiringFiles(ctx, now.Add(-s.cfg.FileTTL))
+	if err != nil {
+		return fmt.Errorf("list expiring files: %w", err)
+	}
+
+	for _, pub := range expiring {
+		if pub.FilePath != "" {
+			if err := os.Remove(pub.FilePath); err != nil && !os.IsNotExist(err) {
+				s.log.Warn("retention: failed to remove file", "publication", pub.UUID, "error", err)
+				continue
+			}
+		}
+
+		pub.Expire()
+		pub.ExpiresAt = now.Add(s.cfg.MetadataTTL - s.cfg.FileTTL)
+
+		if err := s.pubs.Update(ctx, pub); err != nil {
+			return fmt.Errorf("expire publication %s: %w", pub.UUID, err)
+		}
+	}
+
+	expired, err := s.pubs.ListExpiredMetadata(ctx, now)
+	if err != nil {
+		return fmt.Errorf("list expired metadata: %w", err)
+	}
+
+	for _, pub := range expired {
+		if err := s.pubs.Delete(ctx, pub.UUID); err != nil {
+			return fmt.Errorf("delete publication %s: %w", pub.UUID, err)
+		}
+	}
+
+	if err := s.jobs.deleteExpiredJobs(ctx, now.Add(-s.cfg.MetadataTTL)); err != nil {
+		return fmt.Errorf("sweep expired jobs: %w", err)
+	}
+
+	return nil
+}
+
+// RunRetentionLoop runs SweepRetention on the given interval until ctx is
+// cancelled.
+func (s *PublicationService) RunRetentionLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SweepRetention(ctx); err != nil {
+				s.log.Error("retention sweep failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/repository"
+	"github.com/evan-sm/daunrodo/pkg/clock"
+)
+
+// PurgeService deletes jobs, publications and their files matching a source
+// URL or uploader, for operators handling takedown/GDPR erasure requests.
+type PurgeService struct {
+	jobs  repository.JobRepository
+	pubs  repository.PublicationRepository
+	clock clock.Clock
+}
+
+// NewPurgeService builds a PurgeService.
+func NewPurgeService(jobs repository.JobRepository, pubs repository.PublicationRepository) *PurgeService {
+	return &PurgeService{jobs: jobs, pubs: pubs, clock: clock.New()}
+}
+
+// SetClock overrides the clock PurgeJobs uses to compute its OlderThan
+// cutoff; tests can pass a clock.NewFake to exercise cutoff edge cases
+// deterministically. Production wiring never needs this, since
+// NewPurgeService already defaults to clock.New().
+func (s *PurgeService) SetClock(clk clock.Clock) {
+	s.clock = clk
+}
+
+// PurgeRequest selects what Purge removes. At least one of URLPattern or
+// Uploader must be non-empty; both are matched as case-insensitive
+// substrings.
+type PurgeRequest struct {
+	// URLPattern matches against Job.URL and Publication.SourceURL.
+	URLPattern string
+	// Uploader matches against Publication.Author; jobs have no uploader
+	// of their own, so this only affects publications (and, transitively,
+	// the job that produced a matching one).
+	Uploader string
+}
+
+// PurgeReport summarizes what a Purge call removed.
+type PurgeReport struct {
+	JobsDeleted         int      `json:"jobs_deleted"`
+	PublicationsDeleted int      `json:"publications_deleted"`
+	FilesDeleted        int      `json:"files_deleted"`
+	// Errors collects failures removing individual records or files; a
+	// failure on one item doesn't abort the rest of the purge.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Purge deletes every job and publication matching req, along with the
+// publications' on-disk files, and returns a report of what was removed.
+func (s *PurgeService) Purge(ctx context.Context, req PurgeRequest) (*PurgeReport, error) {
+	report := &PurgeReport{}
+
+	pubs, err := s.pubs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list publications: %w", err)
+	}
+
+	matchedJobs := make(map[string]struct{})
+
+	for _, pub := range pubs {
+		if !matchesURL(req.URLPattern, pub.SourceURL) && !matchesUploader(req.Uploader, pub.Author) {
+			continue
+		}
+
+		matchedJobs[pub.JobID.String()] = struct{}{}
+
+		if pub.FilePath != "" {
+			if err := os.Remove(pub.FilePath); err != nil && !os.IsNotExist(err) {
+				report.Errors = append(report.Errors, fmt.Sprintf("remove file for publication %s: %v", pub.UUID, err))
+			} else {
+				report.FilesDeleted++
+			}
+		}
+
+		if err := s.pubs.Delete(ctx, pub.UUID); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("delete publication %s: %v", pub.UUID, err))
+			continue
+		}
+
+		report.PublicationsDeleted++
+	}
+
+	jobs, err := s.jobs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		_, fromPublication := matchedJobs[job.ID.String()]
+		if !fromPublication && !matchesURL(req.URLPattern, job.URL) {
+			continue
+		}
+
+		if err := s.jobs.Delete(ctx, job.ID); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("delete job %s: %v", job.ID, err))
+			continue
+		}
+
+		report.JobsDeleted++
+	}
+
+	return report, nil
+}
+
+// ErrJobNotFinished is returned by PurgeJob when asked to purge a job
+// that's still pending/queued/running/paused/scheduled, since deleting it
+// out from under the worker pool could leave a download running with
+// nothing left to record its result.
+var ErrJobNotFinished = fmt.Errorf("job is not finished")
+
+// PurgeJob immediately deletes a single finished job, along with any
+// publication it produced and that publication's on-disk file, reusing the
+// same removal logic as Purge. It is the handler for DELETE
+// /v1/jobs/{id}?purge=true.
+func (s *PurgeService) PurgeJob(ctx context.Context, jobID uuid.UUID) (*PurgeReport, error) {
+	job, err := s.jobs.Get(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+
+	if !terminalJobStatuses[job.Status()] {
+		return nil, ErrJobNotFinished
+	}
+
+	report := &PurgeReport{}
+	s.purgeJob(ctx, job, report)
+
+	return report, nil
+}
+
+// JobPurgeFilter selects which finished jobs PurgeJobs removes; at least
+// one of Status or OlderThan should be set, otherwise every finished job in
+// the system matches.
+type JobPurgeFilter struct {
+	// Status, if set, restricts deletion to jobs in this one status (e.g.
+	// entity.JobStatusError to clear out failures); any terminal status is
+	// eligible if empty.
+	Status entity.JobStatus
+	// OlderThan, if non-zero, restricts deletion to jobs created more than
+	// this long ago.
+	OlderThan time.Duration
+}
+
+// PurgeJobs deletes every finished job matching filter, along with each
+// one's publication and file. It is the handler for POST /v1/jobs/purge.
+func (s *PurgeService) PurgeJobs(ctx context.Context, filter JobPurgeFilter) (*PurgeReport, error) {
+	jobs, err := s.jobs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	cutoff := s.clock.Now().Add(-filter.OlderThan)
+	report := &PurgeReport{}
+
+	for _, job := range jobs {
+		if !terminalJobStatuses[job.Status()] {
+			continue
+		}
+
+		if filter.Status != "" && job.Status() != filter.Status {
+			continue
+		}
+
+		if filter.OlderThan != 0 && job.CreatedAt.After(cutoff) {
+			continue
+		}
+
+		s.purgeJob(ctx, job, report)
+	}
+
+	return report, nil
+}
+
+// purgeJob deletes job's publication and file (if it has one) and the job
+// record itself, appending any failures to report rather than aborting. A
+// job with no publication but a recorded OutputPath (e.g. an album track,
+// or a job placed under a config.Job.DirectoryLayouts subdirectory; see
+// YtDlpRunner.locateOutputPath) has its file removed directly instead.
+func (s *PurgeService) purgeJob(ctx context.Context, job *entity.Job, report *PurgeReport) {
+	if pub, err := s.pubs.GetByJobID(ctx, job.ID); err == nil {
+		if pub.FilePath != "" {
+			if err := os.Remove(pub.FilePath); err != nil && !os.IsNotExist(err) {
+				report.Errors = append(report.Errors, fmt.Sprintf("remove file for publication %s: %v", pub.UUID, err))
+			} else {
+				report.FilesDeleted++
+			}
+		}
+
+		if err := s.pubs.Delete(ctx, pub.UUID); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("delete publication %s: %v", pub.UUID, err))
+		} else {
+			report.PublicationsDeleted++
+		}
+	} else if job.OutputPath != "" {
+		if err := os.Remove(job.OutputPath); err != nil && !os.IsNotExist(err) {
+			report.Errors = append(report.Errors, fmt.Sprintf("remove output file for job %s: %v", job.ID, err))
+		} else {
+			report.FilesDeleted++
+		}
+	}
+
+	if err := s.jobs.Delete(ctx, job.ID); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("delete job %s: %v", job.ID, err))
+		return
+	}
+
+	report.JobsDeleted++
+}
+
+func matchesURL(pattern, url string) bool {
+	return pattern != "" && strings.Contains(strings.ToLower(url), strings.ToLower(pattern))
+}
+
+func matchesUploader(uploader, author string) bool {
+	return uploader != "" && author != "" && strings.Contains(strings.ToLower(author), strings.ToLower(uploader))
+}
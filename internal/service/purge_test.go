@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/repository/memory"
+	"github.com/evan-sm/daunrodo/pkg/clock"
+)
+
+func TestPurgeJobsOlderThanCutoff(t *testing.T) {
+	ctx := context.Background()
+	jobs := memory.NewJobRepository()
+	pubs := memory.NewPublicationRepository()
+
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(now)
+
+	svc := NewPurgeService(jobs, pubs)
+	svc.SetClock(fake)
+
+	old := entity.NewJob("https://example.com/old", "default")
+	old.SetStatus(entity.JobStatusDone)
+	old.CreatedAt = now.Add(-2 * time.Hour)
+
+	recent := entity.NewJob("https://example.com/recent", "default")
+	recent.SetStatus(entity.JobStatusDone)
+	recent.CreatedAt = now.Add(-30 * time.Minute)
+
+	running := entity.NewJob("https://example.com/running", "default")
+	running.SetStatus(entity.JobStatusRunning)
+	running.CreatedAt = now.Add(-3 * time.Hour)
+
+	for _, j := range []*entity.Job{old, recent, running} {
+		if err := jobs.Create(ctx, j); err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+	}
+
+	report, err := svc.PurgeJobs(ctx, JobPurgeFilter{OlderThan: time.Hour})
+	if err != nil {
+		t.Fatalf("PurgeJobs: %v", err)
+	}
+
+	if report.JobsDeleted != 1 {
+		t.Fatalf("JobsDeleted = %d, want 1", report.JobsDeleted)
+	}
+
+	if _, err := jobs.Get(ctx, old.ID); err == nil {
+		t.Error("old job still exists, want purged")
+	}
+
+	if _, err := jobs.Get(ctx, recent.ID); err != nil {
+		t.Errorf("recent job was purged, want kept: %v", err)
+	}
+
+	if _, err := jobs.Get(ctx, running.ID); err != nil {
+		t.Errorf("running job was purged despite being non-terminal: %v", err)
+	}
+
+	// Advancing the fake clock brings recent past the cutoff too, without
+	// any wall-clock sleep.
+	fake.Advance(2 * time.Hour)
+
+	report, err = svc.PurgeJobs(ctx, JobPurgeFilter{OlderThan: time.Hour})
+	if err != nil {
+		t.Fatalf("PurgeJobs after advance: %v", err)
+	}
+
+	if report.JobsDeleted != 1 {
+		t.Fatalf("JobsDeleted after advance = %d, want 1", report.JobsDeleted)
+	}
+
+	if _, err := jobs.Get(ctx, recent.ID); err == nil {
+		t.Error("recent job still exists after clock advance, want purged")
+	}
+}
+
+func TestPurgeJobsStatusFilter(t *testing.T) {
+	ctx := context.Background()
+	jobs := memory.NewJobRepository()
+	pubs := memory.NewPublicationRepository()
+
+	svc := NewPurgeService(jobs, pubs)
+
+	errored := entity.NewJob("https://example.com/errored", "default")
+	errored.SetStatus(entity.JobStatusError)
+
+	done := entity.NewJob("https://example.com/done", "default")
+	done.SetStatus(entity.JobStatusDone)
+
+	for _, j := range []*entity.Job{errored, done} {
+		if err := jobs.Create(ctx, j); err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+	}
+
+	report, err := svc.PurgeJobs(ctx, JobPurgeFilter{Status: entity.JobStatusError})
+	if err != nil {
+		t.Fatalf("PurgeJobs: %v", err)
+	}
+
+	if report.JobsDeleted != 1 {
+		t.Fatalf("JobsDeleted = %d, want 1", report.JobsDeleted)
+	}
+
+	if _, err := jobs.Get(ctx, errored.ID); err == nil {
+		t.Error("errored job still exists, want purged")
+	}
+
+	if _, err := jobs.Get(ctx, done.ID); err != nil {
+		t.Errorf("done job was purged despite not matching the status filter: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/repository"
+	"github.com/evan-sm/daunrodo/pkg/cron"
+)
+
+// SchedulerService owns recurring downloads: schedules registered against
+// a URL (e.g. a channel page) are periodically re-probed and expanded into
+// child jobs, deduped against a per-schedule yt-dlp download archive so
+// only new items are fetched. See entity.Schedule.
+type SchedulerService struct {
+	schedules  repository.ScheduleRepository
+	jobs       *JobService
+	archiveDir string
+	log        *slog.Logger
+}
+
+// NewSchedulerService builds a SchedulerService. archiveDir is where each
+// schedule's yt-dlp download-archive file is kept, one per schedule ID.
+func NewSchedulerService(schedules repository.ScheduleRepository, jobs *JobService, archiveDir string, log *slog.Logger) *SchedulerService {
+	return &SchedulerService{schedules: schedules, jobs: jobs, archiveDir: archiveDir, log: log}
+}
+
+// Create registers a new schedule for url/preset firing on cronExpr.
+func (s *SchedulerService) Create(ctx context.Context, url, preset, cronExpr string) (*entity.Schedule, error) {
+	if _, err := cron.Parse(cronExpr); err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+
+	schedule := entity.NewSchedule(url, preset, cronExpr, "")
+	schedule.DownloadArchive = s.archiveFile(schedule.ID)
+
+	if err := s.schedules.Create(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("create schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// Get returns a schedule by ID.
+func (s *SchedulerService) Get(ctx context.Context, id uuid.UUID) (*entity.Schedule, error) {
+	schedule, err := s.schedules.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// List returns every registered schedule.
+func (s *SchedulerService) List(ctx context.Context) ([]*entity.Schedule, error) {
+	schedules, err := s.schedules.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// Update applies changes to an existing schedule's URL/preset/cron/enabled
+// fields.
+func (s *SchedulerService) Update(ctx context.Context, id uuid.UUID, url, preset, cronExpr string, enabled bool) (*entity.Schedule, error) {
+	schedule, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cronExpr != "" {
+		if _, err := cron.Parse(cronExpr); err != nil {
+			return nil, fmt.Errorf("invalid cron expression: %w", err)
+		}
+
+		schedule.Cron = cronExpr
+	}
+
+	if url != "" {
+		schedule.URL = url
+	}
+
+	if preset != "" {
+		schedule.Preset = preset
+	}
+
+	schedule.Enabled = enabled
+
+	if err := s.schedules.Update(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("update schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// Delete removes a schedule; it does not affect jobs already spawned from
+// it.
+func (s *SchedulerService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.schedules.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete schedule: %w", err)
+	}
+
+	return nil
+}
+
+// Run polls every tick for schedules due to fire and expands them, until
+// ctx is cancelled. A schedule is due once its cron expression's next
+// firing time after LastRunAt has passed.
+func (s *SchedulerService) Run(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkDue(ctx)
+		}
+	}
+}
+
+func (s *SchedulerService) checkDue(ctx context.Context) {
+	schedules, err := s.schedules.List(ctx)
+	if err != nil {
+		s.log.Error("list schedules", "error", err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, schedule := range schedules {
+		if !schedule.Enabled {
+			continue
+		}
+
+		expr, err := cron.Parse(schedule.Cron)
+		if err != nil {
+			s.log.Error("parse schedule cron", "schedule", schedule.ID, "error", err)
+			continue
+		}
+
+		since := schedule.LastRunAt
+		if since.IsZero() {
+			since = schedule.CreatedAt
+		}
+
+		next := expr.Next(since)
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+
+		s.fire(ctx, schedule, now)
+	}
+}
+
+func (s *SchedulerService) fire(ctx context.Context, schedule *entity.Schedule, at time.Time) {
+	schedule.LastRunAt = at
+
+	if err := s.schedules.Update(ctx, schedule); err != nil {
+		s.log.Error("update schedule", "schedule", schedule.ID, "error", err)
+	}
+
+	if _, err := s.jobs.EnqueuePlaylist(ctx, schedule.URL, schedule.Preset, schedule.DownloadArchive, 0); err != nil {
+		s.log.Error("fire schedule", "schedule", schedule.ID, "error", err)
+	}
+}
+
+func (s *SchedulerService) archiveFile(id uuid.UUID) string {
+	return filepath.Join(s.archiveDir, id.String()+".txt")
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/evan-sm/daunrodo/internal/config"
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/repository"
+	"github.com/evan-sm/daunrodo/internal/worker"
+)
+
+// FailedJobReport summarizes one failed job for the support bundle: its
+// reproducible command lines (see execenv.CommandLine) and the error it
+// ultimately failed with.
+type FailedJobReport struct {
+	ID       string            `json:"id"`
+	URL      string            `json:"url"`
+	Error    string            `json:"error"`
+	Attempt  int               `json:"attempt"`
+	Commands []string          `json:"commands"`
+	Events   []entity.JobEvent `json:"events"`
+}
+
+// VersionInfo reports the running build, as exposed by runtime/debug.
+type VersionInfo struct {
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	Modified  bool   `json:"modified"`
+}
+
+// SupportBundleService assembles a zip of redacted diagnostics for bug
+// reports: config, version, dependency status, worker pool load and the
+// last few failed jobs' command lines and errors.
+type SupportBundleService struct {
+	cfg           config.Config
+	jobs          repository.JobRepository
+	pool          *worker.Pool
+	depsDir       string
+	maxFailedJobs int
+}
+
+// NewSupportBundleService builds a SupportBundleService. maxFailedJobs
+// bounds how many recent failed jobs are included, newest first.
+func NewSupportBundleService(cfg config.Config, jobs repository.JobRepository, pool *worker.Pool, depsDir string, maxFailedJobs int) *SupportBundleService {
+	return &SupportBundleService{cfg: cfg, jobs: jobs, pool: pool, depsDir: depsDir, maxFailedJobs: maxFailedJobs}
+}
+
+// Generate writes a zip support bundle to w.
+func (s *SupportBundleService) Generate(ctx context.Context, w *zip.Writer) error {
+	if err := writeJSONEntry(w, "config.json", s.cfg.Redacted()); err != nil {
+		return err
+	}
+
+	if err := writeJSONEntry(w, "version.json", versionInfo()); err != nil {
+		return err
+	}
+
+	if s.pool != nil {
+		if err := writeJSONEntry(w, "pool_stats.json", s.pool.Stats()); err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSONEntry(w, "deps.json", s.depsStatus()); err != nil {
+		return err
+	}
+
+	failed, err := s.failedJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := writeJSONEntry(w, "failed_jobs.json", failed); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SupportBundleService) failedJobs(ctx context.Context) ([]FailedJobReport, error) {
+	jobs, err := s.jobs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	var reports []FailedJobReport
+
+	for _, job := range jobs {
+		if job.Status() != entity.JobStatusError {
+			continue
+		}
+
+		reports = append(reports, FailedJobReport{
+			ID:       job.ID.String(),
+			URL:      job.URL,
+			Error:    job.Error(),
+			Attempt:  job.Attempt,
+			Commands: jobCommands(job),
+			Events:   job.EventsSnapshot(),
+		})
+
+		if len(reports) == s.maxFailedJobs {
+			break
+		}
+	}
+
+	return reports, nil
+}
+
+// jobCommands extracts the reproducible command lines recorded on job's
+// event log, see getJobCommands.
+func jobCommands(job *entity.Job) []string {
+	var commands []string
+
+	for _, ev := range job.EventsSnapshot() {
+		if ev.Type == "command" {
+			commands = append(commands, ev.Message)
+		}
+	}
+
+	return commands
+}
+
+// depsStatus lists the optional dependency binaries currently cached under
+// depsDir.
+func (s *SupportBundleService) depsStatus() []string {
+	entries, err := os.ReadDir(s.depsDir)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	return names
+}
+
+func versionInfo() VersionInfo {
+	info := VersionInfo{GoVersion: "unknown"}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.GoVersion = build.GoVersion
+
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+
+	return info
+}
+
+func writeJSONEntry(w *zip.Writer, name string, v any) error {
+	f, err := w.Create(filepath.ToSlash(name))
+	if err != nil {
+		return fmt.Errorf("create zip entry %s: %w", name, err)
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+
+	return nil
+}
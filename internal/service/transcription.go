@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/evan-sm/daunrodo/internal/depmanager"
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/execenv"
+	"github.com/evan-sm/daunrodo/internal/repository"
+)
+
+// TranscriptionService runs whisper.cpp transcriptions on a bounded worker
+// pool separate from the download workers, so a slow transcription never
+// blocks new downloads from starting.
+type TranscriptionService struct {
+	pubs  repository.PublicationRepository
+	deps  *depmanager.Manager
+	model string
+	tasks chan entity.Publication
+	log   *slog.Logger
+}
+
+// NewTranscriptionService builds a TranscriptionService with queueDepth
+// pending tasks and concurrency parallel workers.
+func NewTranscriptionService(pubs repository.PublicationRepository, deps *depmanager.Manager, model string, queueDepth, concurrency int, log *slog.Logger) *TranscriptionService {
+	s := &TranscriptionService{
+		pubs:  pubs,
+		deps:  deps,
+		model: model,
+		tasks: make(chan entity.Publication, queueDepth),
+		log:   log,
+	}
+
+	for i := 0; i < concurrency; i++ {
+		go s.worker()
+	}
+
+	return s
+}
+
+// Enqueue schedules pub for transcription, returning false if the queue is
+// full and the caller should retry later.
+func (s *TranscriptionService) Enqueue(pub entity.Publication) bool {
+	select {
+	case s.tasks <- pub:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *TranscriptionService) worker() {
+	for pub := range s.tasks {
+		if err := s.transcribe(context.Background(), pub); err != nil {
+			s.log.Error("transcription failed", "publication", pub.UUID, "error", err)
+		}
+	}
+}
+
+func (s *TranscriptionService) transcribe(ctx context.Context, pub entity.Publication) error {
+	binPath, err := s.deps.Ensure(ctx, depmanager.Dependency{Name: "whisper.cpp", BinaryName: "whisper-cli"})
+	if err != nil {
+		return fmt.Errorf("ensure whisper.cpp: %w", err)
+	}
+
+	artifactPath, lang, err := runWhisper(ctx, binPath, s.model, pub.FilePath)
+	if err != nil {
+		return fmt.Errorf("run whisper: %w", err)
+	}
+
+	artifact := entity.NewArtifact(entity.ArtifactTypeTranscript, artifactPath)
+	artifact.Language = lang
+
+	pub.AddArtifact(artifact)
+
+	if err := s.pubs.Update(ctx, &pub); err != nil {
+		return fmt.Errorf("save transcript artifact: %w", err)
+	}
+
+	return nil
+}
+
+// runWhisper transcribes audioPath, returning the path to the generated SRT
+// file and the detected language.
+func runWhisper(ctx context.Context, binPath, model, audioPath string) (artifactPath, language string, err error) {
+	outPath := audioPath + ".srt"
+
+	cmd := execenv.Command(ctx, filepath.Dir(audioPath), binPath, "-m", model, "-f", audioPath, "-osrt", "-of", audioPath, "-l", "auto")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", "", fmt.Errorf("whisper-cli: %w: %s", err, stderr.String())
+	}
+
+	return outPath, detectedLanguage(stderr.String()), nil
+}
+
+// detectedLanguage extracts the "auto-detected language: xx" line whisper.cpp
+// prints to stderr, falling back to an empty string when not found.
+func detectedLanguage(stderrOutput string) string {
+	const marker = "auto-detected language: "
+
+	idx := strings.Index(stderrOutput, marker)
+	if idx == -1 {
+		return ""
+	}
+
+	rest := stderrOutput[idx+len(marker):]
+	if end := strings.IndexAny(rest, " \n"); end != -1 {
+		rest = rest[:end]
+	}
+
+	return rest
+}
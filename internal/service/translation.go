@@ -0,0 +1,107 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/execenv"
+)
+
+// TranslationService translates subtitle artifacts via either a
+// LibreTranslate HTTP endpoint or an external command, selected by which
+// field is non-empty.
+type TranslationService struct {
+	endpoint string // LibreTranslate base URL, e.g. http://localhost:5000
+	command  string // external command template, "{src} {lang} {out}" args
+}
+
+// NewTranslationService builds a TranslationService. Exactly one of
+// endpoint/command is expected to be set.
+func NewTranslationService(endpoint, command string) *TranslationService {
+	return &TranslationService{endpoint: endpoint, command: command}
+}
+
+// Translate reads the subtitle artifact at srcPath and writes a translated
+// copy for each target language, returning one artifact per language.
+func (s *TranslationService) Translate(ctx context.Context, srcPath string, targetLangs []string) ([]entity.Artifact, error) {
+	var artifacts []entity.Artifact
+
+	for _, lang := range targetLangs {
+		outPath := strings.TrimSuffix(srcPath, ".srt") + "." + lang + ".srt"
+
+		if err := s.translateOne(ctx, srcPath, outPath, lang); err != nil {
+			return artifacts, fmt.Errorf("translate to %s: %w", lang, err)
+		}
+
+		artifact := entity.NewArtifact(entity.ArtifactTypeTranscript, outPath)
+		artifact.Language = lang
+		artifacts = append(artifacts, artifact)
+	}
+
+	return artifacts, nil
+}
+
+func (s *TranslationService) translateOne(ctx context.Context, srcPath, outPath, lang string) error {
+	if s.command != "" {
+		return s.translateViaCommand(ctx, srcPath, outPath, lang)
+	}
+
+	return s.translateViaLibreTranslate(ctx, srcPath, outPath, lang)
+}
+
+func (s *TranslationService) translateViaCommand(ctx context.Context, srcPath, outPath, lang string) error {
+	cmd := execenv.Command(ctx, filepath.Dir(srcPath), s.command, srcPath, lang, outPath)
+	return cmd.Run()
+}
+
+type libreTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText string `json:"translatedText"`
+}
+
+func (s *TranslationService) translateViaLibreTranslate(ctx context.Context, srcPath, outPath, lang string) error {
+	text, err := os.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(libreTranslateRequest{Q: string(text), Source: "auto", Target: lang})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("libretranslate: unexpected status %s", resp.Status)
+	}
+
+	var out libreTranslateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return err
+	}
+
+	return os.WriteFile(outPath, []byte(out.TranslatedText), 0o644)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WatchFolder polls dir for .txt/.csv batch files on the given interval,
+// imports each one via ImportService and renames it to "<name>.imported" so
+// it isn't picked up again. It blocks until ctx is cancelled.
+func (s *ImportService) WatchFolder(ctx context.Context, dir string, interval time.Duration, log *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.scanFolder(ctx, dir, log)
+		}
+	}
+}
+
+func (s *ImportService) scanFolder(ctx context.Context, dir string, log *slog.Logger) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Warn("watch folder: read dir failed", "dir", dir, "error", err)
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".txt" && ext != ".csv" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		if err := s.importFile(ctx, path); err != nil {
+			log.Error("watch folder: import failed", "file", path, "error", err)
+			continue
+		}
+
+		if err := os.Rename(path, path+".imported"); err != nil {
+			log.Warn("watch folder: rename after import failed", "file", path, "error", err)
+		}
+	}
+}
+
+func (s *ImportService) importFile(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, _, err = s.Import(ctx, path, "", f)
+
+	return err
+}
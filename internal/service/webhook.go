@@ -0,0 +1,239 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/config"
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/pkg/logger"
+	"github.com/evan-sm/daunrodo/pkg/retry"
+	"github.com/evan-sm/daunrodo/pkg/safehttp"
+)
+
+// maxWebhookRedirects bounds how many redirects deliver will follow, same
+// as safehttp.Client.
+const maxWebhookRedirects = 5
+
+// errPermanentWebhookStatus wraps a webhook response status retry.Policy
+// treats as not worth retrying: a 4xx other than 429 means there's nothing
+// about the request a retry would fix.
+type errPermanentWebhookStatus struct {
+	status int
+}
+
+func (e *errPermanentWebhookStatus) Error() string {
+	return fmt.Sprintf("webhook returned %d", e.status)
+}
+
+// errPermanentWebhookURL wraps a URL that failed ValidateWebhookURL: no
+// retry would fix an SSRF-blocked or malformed destination.
+type errPermanentWebhookURL struct {
+	err error
+}
+
+func (e *errPermanentWebhookURL) Error() string {
+	return fmt.Sprintf("invalid webhook url: %s", e.err)
+}
+
+func (e *errPermanentWebhookURL) Unwrap() error {
+	return e.err
+}
+
+// allowedWebhookSchemes are the only schemes WebhookNotifier will POST to.
+var allowedWebhookSchemes = map[string]bool{"http": true, "https": true}
+
+// ValidateWebhookURL checks that raw is an absolute http(s) URL and,
+// unless allowPrivateHosts is set, that its host isn't a literal
+// loopback, private, or link-local address. job.Webhook (enqueue
+// body/query, /v1/me/defaults) and config.Webhook.URL are both fully
+// caller/operator-controlled, and deliver POSTs to them with server-side
+// retries -- without this check, a caller could point a webhook at an
+// internal service or cloud metadata endpoint (e.g.
+// http://169.254.169.254/...) and have the server dutifully retry the
+// request on failure. allowPrivateHosts exists for self-hosted
+// deployments that legitimately want to notify an internal service; see
+// config.Webhook.AllowPrivateHosts.
+func ValidateWebhookURL(raw string, allowPrivateHosts bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New("must be an absolute http(s) URL")
+	}
+
+	if !allowedWebhookSchemes[u.Scheme] {
+		return fmt.Errorf("scheme %q is not allowed, use http or https", u.Scheme)
+	}
+
+	if allowPrivateHosts {
+		return nil
+	}
+
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return errors.New("localhost is not an allowed webhook host")
+	}
+
+	if ip := net.ParseIP(host); ip != nil && !safehttp.IsPublic(ip) {
+		return errors.New("host resolves to a loopback, private, or link-local address")
+	}
+
+	return nil
+}
+
+// WebhookEvent is the JSON payload POSTed to a job's webhook on every
+// status transition.
+type WebhookEvent struct {
+	JobID     string    `json:"job_id"`
+	URL       string    `json:"url"`
+	Event     string    `json:"event"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Attempt   int       `json:"attempt"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookNotifier POSTs WebhookEvent payloads to a job's webhook URL,
+// signing them with HMAC-SHA256 when a secret is configured and retrying
+// failed deliveries with exponential backoff.
+type WebhookNotifier struct {
+	defaultURL        string
+	secret            string
+	allowPrivateHosts bool
+	retry             retry.Policy
+	client            *http.Client
+	log               *slog.Logger
+}
+
+// NewWebhookNotifier builds a WebhookNotifier from cfg.
+func NewWebhookNotifier(cfg config.Webhook, log *slog.Logger) *WebhookNotifier {
+	return &WebhookNotifier{
+		defaultURL:        cfg.URL,
+		secret:            cfg.Secret,
+		allowPrivateHosts: cfg.AllowPrivateHosts,
+		retry: retry.Policy{
+			MaxAttempts: cfg.MaxAttempts,
+			Base:        cfg.BackoffBase,
+			Retryable: func(err error) bool {
+				var status *errPermanentWebhookStatus
+				if errors.As(err, &status) {
+					return false
+				}
+
+				var badURL *errPermanentWebhookURL
+
+				return !errors.As(err, &badURL)
+			},
+		},
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: safehttp.DialContext(10*time.Second, cfg.AllowPrivateHosts),
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxWebhookRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxWebhookRedirects)
+				}
+
+				if err := ValidateWebhookURL(req.URL.String(), cfg.AllowPrivateHosts); err != nil {
+					return fmt.Errorf("redirect target: %w", err)
+				}
+
+				return nil
+			},
+		},
+		log: log,
+	}
+}
+
+// Notify POSTs event for job to job's webhook (job.Webhook, falling back to
+// the server default), doing nothing if neither is set. Delivery happens
+// synchronously with retries; callers that don't want to block should run it
+// in a goroutine.
+func (n *WebhookNotifier) Notify(ctx context.Context, job *entity.Job, event string) {
+	ctx = logger.WithJobID(ctx, job.ID.String())
+
+	url := job.Webhook
+	if url == "" {
+		url = n.defaultURL
+	}
+
+	if url == "" {
+		return
+	}
+
+	payload := WebhookEvent{
+		JobID:     job.ID.String(),
+		URL:       job.URL,
+		Event:     event,
+		Status:    string(job.Status()),
+		Error:     job.Error(),
+		Attempt:   job.Attempt,
+		Timestamp: time.Now(),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		n.log.ErrorContext(ctx, "webhook: marshal payload", "error", err)
+		return
+	}
+
+	err = retry.Do(ctx, n.retry, func(int) error {
+		return n.deliver(ctx, url, body)
+	})
+	if err != nil {
+		n.log.ErrorContext(ctx, "webhook: delivery failed", "url", url, "error", err)
+	}
+}
+
+func (n *WebhookNotifier) deliver(ctx context.Context, target string, body []byte) error {
+	if err := ValidateWebhookURL(target, n.allowPrivateHosts); err != nil {
+		return &errPermanentWebhookURL{err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if n.secret != "" {
+		req.Header.Set("X-Daunrodo-Signature", "sha256="+sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return &errPermanentWebhookStatus{status: resp.StatusCode}
+		}
+
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of body keyed by secret.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,151 @@
+// Package simulate drives the worker pool with synthetic jobs against a
+// mock downloader, for evaluating scheduler, limiter and autoscaling
+// settings offline instead of against real traffic; see daunrodo simulate.
+package simulate
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Profile configures one simulation run: how many synthetic jobs to submit,
+// spread across how many submitting identities, and the pool settings
+// (worker counts, per-domain/profile limits, queue depth) to evaluate them
+// against. Loaded from a flat "key: value" file (a valid subset of YAML,
+// e.g. profiles/mixed.yaml); fields not present keep their DefaultProfile
+// value.
+type Profile struct {
+	Name string
+	// JobCount is how many synthetic jobs Run submits in total.
+	JobCount int
+	// Identities is how many distinct Job.Submitter values JobCount is
+	// spread evenly across, to exercise the pool's per-identity fairness.
+	Identities int
+	// ArrivalInterval is how long Run waits between submitting successive
+	// jobs, simulating steady incoming traffic rather than one burst.
+	ArrivalInterval time.Duration
+	MinWorkers      int
+	MaxWorkers      int
+	QueueDepth      int
+	// ProfileConcurrency caps jobs sharing a cookie/account profile; see
+	// worker.New. Simulated jobs are spread across Identities profiles the
+	// same way they're spread across submitter identities.
+	ProfileConcurrency int
+	// DomainConcurrency lists "domain=limit" pairs, same format and
+	// meaning as config.Job.DomainConcurrency.
+	DomainConcurrency []string
+	// MeanDuration and DurationJitter describe the mock downloader's
+	// simulated run time per job: uniformly distributed across
+	// [MeanDuration-DurationJitter, MeanDuration+DurationJitter].
+	MeanDuration   time.Duration
+	DurationJitter time.Duration
+	// FailRate is the fraction (0-1) of simulated jobs the mock downloader
+	// fails, to see how retries and backoff interact with the pool under
+	// load.
+	FailRate float64
+}
+
+// DefaultProfile is the baseline every loaded Profile starts from, so a
+// profile file only needs to list the fields it wants to override.
+func DefaultProfile() Profile {
+	return Profile{
+		Name:               "default",
+		JobCount:           100,
+		Identities:         10,
+		ArrivalInterval:    50 * time.Millisecond,
+		MinWorkers:         1,
+		MaxWorkers:         4,
+		QueueDepth:         256,
+		ProfileConcurrency: 1,
+		MeanDuration:       2 * time.Second,
+		DurationJitter:     500 * time.Millisecond,
+		FailRate:           0,
+	}
+}
+
+// LoadProfile reads a flat "key: value" profile file, one setting per
+// line, "#" starting a comment and blank lines ignored. Unrecognized or
+// malformed keys are skipped rather than failing the whole file, same as
+// worker.ParseDomainConcurrency, since a typo in one setting shouldn't
+// block evaluating the rest of the profile.
+func LoadProfile(path string) (Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("load profile: %w", err)
+	}
+
+	p := DefaultProfile()
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		applyProfileField(&p, key, value)
+	}
+
+	return p, nil
+}
+
+func applyProfileField(p *Profile, key, value string) {
+	switch key {
+	case "name":
+		p.Name = value
+	case "job_count":
+		if n, err := strconv.Atoi(value); err == nil {
+			p.JobCount = n
+		}
+	case "identities":
+		if n, err := strconv.Atoi(value); err == nil {
+			p.Identities = n
+		}
+	case "arrival_interval":
+		if d, err := time.ParseDuration(value); err == nil {
+			p.ArrivalInterval = d
+		}
+	case "min_workers":
+		if n, err := strconv.Atoi(value); err == nil {
+			p.MinWorkers = n
+		}
+	case "max_workers":
+		if n, err := strconv.Atoi(value); err == nil {
+			p.MaxWorkers = n
+		}
+	case "queue_depth":
+		if n, err := strconv.Atoi(value); err == nil {
+			p.QueueDepth = n
+		}
+	case "profile_concurrency":
+		if n, err := strconv.Atoi(value); err == nil {
+			p.ProfileConcurrency = n
+		}
+	case "domain_concurrency":
+		if value != "" {
+			p.DomainConcurrency = strings.Split(value, ",")
+		}
+	case "mean_duration":
+		if d, err := time.ParseDuration(value); err == nil {
+			p.MeanDuration = d
+		}
+	case "duration_jitter":
+		if d, err := time.ParseDuration(value); err == nil {
+			p.DurationJitter = d
+		}
+	case "fail_rate":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			p.FailRate = f
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package simulate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report summarizes one simulation run's throughput and latency, printed by
+// daunrodo simulate after Run returns.
+type Report struct {
+	Profile      string
+	JobCount     int
+	Succeeded    int
+	Failed       int
+	Elapsed      time.Duration
+	// ThroughputPerSec is JobCount/Elapsed, jobs finished per second across
+	// the whole run.
+	ThroughputPerSec float64
+	// QueueWaitP50/P95/P99 are percentiles of how long a job sat queued
+	// before a worker picked it up; LatencyP50/P95/P99 are percentiles of
+	// queue wait plus simulated run time, i.e. total submit-to-finish time.
+	QueueWaitP50, QueueWaitP95, QueueWaitP99 time.Duration
+	LatencyP50, LatencyP95, LatencyP99       time.Duration
+}
+
+// buildReport summarizes results into a Report, empty if results is empty.
+func buildReport(profile Profile, results []result, elapsed time.Duration) Report {
+	r := Report{
+		Profile:  profile.Name,
+		JobCount: len(results),
+		Elapsed:  elapsed,
+	}
+
+	if len(results) == 0 {
+		return r
+	}
+
+	queueWaits := make([]time.Duration, len(results))
+	latencies := make([]time.Duration, len(results))
+
+	for i, res := range results {
+		if res.succeeded {
+			r.Succeeded++
+		} else {
+			r.Failed++
+		}
+
+		queueWaits[i] = res.queueWait
+		latencies[i] = res.queueWait + res.runTime
+	}
+
+	sort.Slice(queueWaits, func(i, j int) bool { return queueWaits[i] < queueWaits[j] })
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	r.QueueWaitP50 = percentile(queueWaits, 0.50)
+	r.QueueWaitP95 = percentile(queueWaits, 0.95)
+	r.QueueWaitP99 = percentile(queueWaits, 0.99)
+	r.LatencyP50 = percentile(latencies, 0.50)
+	r.LatencyP95 = percentile(latencies, 0.95)
+	r.LatencyP99 = percentile(latencies, 0.99)
+
+	if elapsed > 0 {
+		r.ThroughputPerSec = float64(len(results)) / elapsed.Seconds()
+	}
+
+	return r
+}
+
+// percentile returns the p-th percentile (0-1) of sorted, a slice already
+// in ascending order. Returns 0 for an empty slice.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	idx := int(p * float64(len(sorted)-1))
+
+	return sorted[idx]
+}
+
+// String renders r as a short human-readable summary for the simulate
+// command's stdout.
+func (r Report) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "profile: %s\n", r.Profile)
+	fmt.Fprintf(&b, "jobs: %d (%d succeeded, %d failed)\n", r.JobCount, r.Succeeded, r.Failed)
+	fmt.Fprintf(&b, "elapsed: %s\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(&b, "throughput: %.2f jobs/sec\n", r.ThroughputPerSec)
+	fmt.Fprintf(&b, "queue wait: p50=%s p95=%s p99=%s\n",
+		r.QueueWaitP50.Round(time.Millisecond), r.QueueWaitP95.Round(time.Millisecond), r.QueueWaitP99.Round(time.Millisecond))
+	fmt.Fprintf(&b, "latency:    p50=%s p95=%s p99=%s\n",
+		r.LatencyP50.Round(time.Millisecond), r.LatencyP95.Round(time.Millisecond), r.LatencyP99.Round(time.Millisecond))
+
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package simulate
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// errSimulatedFailure is returned by MockRunner.Run for the FailRate
+// fraction of jobs it's configured to fail.
+var errSimulatedFailure = errors.New("simulated downloader failure")
+
+// result records the timing and outcome of one simulated job, fed into
+// Recorder by MockRunner.Run and summarized into a Report once the
+// simulation finishes.
+type result struct {
+	queueWait time.Duration
+	runTime   time.Duration
+	succeeded bool
+}
+
+// Recorder collects result values across every worker goroutine running
+// MockRunner concurrently; a plain slice behind a mutex, the same shape as
+// worker.Pool's own queuedJob bookkeeping, sized for the simulation's
+// JobCount up front to avoid reallocating under load.
+type Recorder struct {
+	mu      sync.Mutex
+	results []result
+}
+
+// NewRecorder builds a Recorder pre-sized for capacity jobs.
+func NewRecorder(capacity int) *Recorder {
+	return &Recorder{results: make([]result, 0, capacity)}
+}
+
+func (rec *Recorder) record(r result) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.results = append(rec.results, r)
+}
+
+// len reports how many results have been recorded so far.
+func (rec *Recorder) len() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return len(rec.results)
+}
+
+// snapshot returns a copy of every result recorded so far.
+func (rec *Recorder) snapshot() []result {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	out := make([]result, len(rec.results))
+	copy(out, rec.results)
+
+	return out
+}
+
+// MockRunner implements worker.Runner by sleeping for a duration drawn from
+// the profile's MeanDuration/DurationJitter instead of shelling out to a
+// real downloader, failing FailRate of the time, so Run can evaluate pool
+// behavior without touching a network or filesystem.
+type MockRunner struct {
+	meanDuration   time.Duration
+	durationJitter time.Duration
+	failRate       float64
+	recorder       *Recorder
+}
+
+// NewMockRunner builds a MockRunner drawing simulated durations from p and
+// recording every job's outcome into rec.
+func NewMockRunner(p Profile, rec *Recorder) *MockRunner {
+	return &MockRunner{
+		meanDuration:   p.MeanDuration,
+		durationJitter: p.DurationJitter,
+		failRate:       p.FailRate,
+		recorder:       rec,
+	}
+}
+
+// Run simulates downloading job, sleeping for a jittered duration and
+// failing at the configured rate, then recording the attempt's queue wait
+// (CreatedAt to pickup) and run time into m.recorder. ctx cancellation
+// (e.g. the simulation's own deadline) ends the sleep early and counts as
+// a failure, same as a real runner's context-cancelled download.
+func (m *MockRunner) Run(ctx context.Context, job *entity.Job) error {
+	startedAt := time.Now()
+	job.StartedAt = &startedAt
+	job.SetStatus(entity.JobStatusRunning)
+
+	dur := m.meanDuration
+	if m.durationJitter > 0 {
+		dur += time.Duration(rand.Int63n(int64(2*m.durationJitter))) - m.durationJitter
+		if dur < 0 {
+			dur = 0
+		}
+	}
+
+	var runErr error
+
+	select {
+	case <-time.After(dur):
+		if m.failRate > 0 && rand.Float64() < m.failRate {
+			runErr = errSimulatedFailure
+		}
+	case <-ctx.Done():
+		runErr = ctx.Err()
+	}
+
+	finishedAt := time.Now()
+
+	if runErr != nil {
+		job.SetStatus(entity.JobStatusError)
+		job.SetError(runErr.Error())
+	} else {
+		job.SetStatus(entity.JobStatusDone)
+	}
+
+	m.recorder.record(result{
+		queueWait: startedAt.Sub(job.CreatedAt),
+		runTime:   finishedAt.Sub(startedAt),
+		succeeded: runErr == nil,
+	})
+
+	return runErr
+}
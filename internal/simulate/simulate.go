@@ -0,0 +1,84 @@
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/internal/worker"
+)
+
+// drainPollInterval is how often Run polls the pool to see whether every
+// submitted job has finished.
+const drainPollInterval = 100 * time.Millisecond
+
+// Run drives a worker.Pool configured from p with MockRunner in place of a
+// real downloader, submitting p.JobCount synthetic jobs spread across
+// p.Identities submitters at p.ArrivalInterval apart, then waits for every
+// job to finish (or ctx to be cancelled) and summarizes throughput and
+// latency into a Report.
+func Run(ctx context.Context, p Profile, log *slog.Logger) (Report, error) {
+	rec := NewRecorder(p.JobCount)
+	runner := NewMockRunner(p, rec)
+	domainLimiter := worker.NewDomainLimiter(worker.ParseDomainConcurrency(p.DomainConcurrency))
+
+	pool := worker.New(runner, p.QueueDepth, p.MinWorkers, p.MaxWorkers, p.ProfileConcurrency, domainLimiter, 0, nil, log, 0, 0, nil)
+
+	start := time.Now()
+
+	if p.Identities < 1 {
+		p.Identities = 1
+	}
+
+	for i := 0; i < p.JobCount; i++ {
+		identity := "sim-" + strconv.Itoa(i%p.Identities)
+
+		job := entity.NewJob(fmt.Sprintf("https://example.com/sim/%d", i), "original")
+		job.Submitter = identity
+		job.Profile = identity
+		job.SetStatus(entity.JobStatusQueued)
+
+		if err := pool.Submit(job); err != nil {
+			return Report{}, fmt.Errorf("submit simulated job %d: %w", i, err)
+		}
+
+		if p.ArrivalInterval > 0 {
+			select {
+			case <-time.After(p.ArrivalInterval):
+			case <-ctx.Done():
+				return Report{}, ctx.Err()
+			}
+		}
+	}
+
+	if err := waitForCompletion(ctx, rec, p.JobCount); err != nil {
+		return Report{}, err
+	}
+
+	elapsed := time.Since(start)
+	pool.Shutdown(ctx)
+
+	return buildReport(p, rec.snapshot(), elapsed), nil
+}
+
+// waitForCompletion blocks until rec has recorded a result for every
+// submitted job, or ctx is cancelled.
+func waitForCompletion(ctx context.Context, rec *Recorder, total int) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if rec.len() >= total {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
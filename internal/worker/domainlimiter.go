@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ParseDomainConcurrency parses "domain=limit" pairs (as found in
+// config.Job.DomainConcurrency) into the map NewDomainLimiter expects,
+// skipping malformed entries since a bad config value shouldn't take the
+// server down.
+func ParseDomainConcurrency(pairs []string) map[string]int {
+	capacities := make(map[string]int, len(pairs))
+
+	for _, pair := range pairs {
+		domain, limitStr, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil {
+			continue
+		}
+
+		capacities[strings.TrimSpace(domain)] = limit
+	}
+
+	return capacities
+}
+
+// DomainLimiter caps concurrent jobs per source domain (e.g. max 1
+// simultaneous youtube.com job, 3 instagram.com jobs), distinct from
+// KeyedLimiter's single shared capacity since each domain needs its own
+// limit. A domain with no configured capacity, or capacity <=0, is
+// unlimited.
+type DomainLimiter struct {
+	capacities map[string]int
+
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+// NewDomainLimiter builds a DomainLimiter from domain=>capacity pairs, e.g.
+// parsed from config.Job.DomainConcurrency.
+func NewDomainLimiter(capacities map[string]int) *DomainLimiter {
+	return &DomainLimiter{capacities: capacities, sems: make(map[string]chan struct{})}
+}
+
+// Acquire blocks until a slot for rawURL's domain is available. A domain
+// that isn't configured, or a nil DomainLimiter, never blocks.
+func (l *DomainLimiter) Acquire(rawURL string) {
+	if l == nil {
+		return
+	}
+
+	domain := Domain(rawURL)
+
+	if limit := l.capacities[domain]; limit <= 0 {
+		return
+	}
+
+	l.sem(domain) <- struct{}{}
+}
+
+// Release frees the slot acquired by Acquire for the same rawURL.
+func (l *DomainLimiter) Release(rawURL string) {
+	if l == nil {
+		return
+	}
+
+	domain := Domain(rawURL)
+
+	if limit := l.capacities[domain]; limit <= 0 {
+		return
+	}
+
+	<-l.sem(domain)
+}
+
+func (l *DomainLimiter) sem(domain string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.sems[domain]
+	if !ok {
+		s = make(chan struct{}, l.capacities[domain])
+		l.sems[domain] = s
+	}
+
+	return s
+}
+
+// Domain extracts the URL's host with any leading "www." removed (e.g.
+// "youtube.com" from "https://www.youtube.com/watch?v=..."; other
+// subdomains such as "m.youtube.com" are kept) used to key per-domain limits and
+// auto-rules. It returns the raw input on parse failure so limiter lookups
+// just treat it as an unconfigured, unlimited "domain".
+func Domain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	host := u.Hostname()
+
+	const wwwPrefix = "www."
+	if len(host) > len(wwwPrefix) && host[:len(wwwPrefix)] == wwwPrefix {
+		host = host[len(wwwPrefix):]
+	}
+
+	return host
+}
@@ -0,0 +1,53 @@
+package worker
+
+import "sync"
+
+// KeyedLimiter caps how many concurrent operations may run under the same
+// key, independent of the overall worker pool size. It's used, for example,
+// to serialize jobs that share a cookie/account profile so daunrodo doesn't
+// trip a platform's anti-abuse systems on that account.
+type KeyedLimiter struct {
+	capacity int
+
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+// NewKeyedLimiter builds a KeyedLimiter allowing up to capacity concurrent
+// holders per key. capacity <= 0 means unlimited (Acquire/Release are
+// no-ops).
+func NewKeyedLimiter(capacity int) *KeyedLimiter {
+	return &KeyedLimiter{capacity: capacity, sems: make(map[string]chan struct{})}
+}
+
+// Acquire blocks until a slot for key is available, or returns immediately
+// if the limiter is unlimited or key is empty (no grouping requested).
+func (l *KeyedLimiter) Acquire(key string) {
+	if l.capacity <= 0 || key == "" {
+		return
+	}
+
+	l.sem(key) <- struct{}{}
+}
+
+// Release frees a previously acquired slot for key.
+func (l *KeyedLimiter) Release(key string) {
+	if l.capacity <= 0 || key == "" {
+		return
+	}
+
+	<-l.sem(key)
+}
+
+func (l *KeyedLimiter) sem(key string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sem, ok := l.sems[key]
+	if !ok {
+		sem = make(chan struct{}, l.capacity)
+		l.sems[key] = sem
+	}
+
+	return sem
+}
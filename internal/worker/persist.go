@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+)
+
+// SaveQueue persists jobs (typically the unfinished portion of a Pool's
+// queue returned by Shutdown) to path as JSON, so they can be restored with
+// LoadQueue on the next startup. An empty path disables persistence. An
+// empty jobs slice removes any previously persisted file, so a clean
+// shutdown doesn't leave stale entries behind.
+func SaveQueue(path string, jobs []*entity.Job) error {
+	if path == "" {
+		return nil
+	}
+
+	if len(jobs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove queue file: %w", err)
+		}
+
+		return nil
+	}
+
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		return fmt.Errorf("marshal queue: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write queue file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadQueue reads jobs persisted by SaveQueue. It returns a nil slice, not
+// an error, if path is empty or the file doesn't exist yet.
+func LoadQueue(path string) ([]*entity.Job, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read queue file: %w", err)
+	}
+
+	var jobs []*entity.Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("unmarshal queue: %w", err)
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,560 @@
+// Package worker runs download jobs on a bounded pool of goroutines, with
+// keyed limiters layered on top for per-domain and per-account-profile
+// concurrency caps.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/evan-sm/daunrodo/internal/entity"
+	"github.com/evan-sm/daunrodo/pkg/logger"
+	"github.com/evan-sm/daunrodo/pkg/memguard"
+)
+
+// memGuardPollInterval is how often a worker paused by a tripped memGuard
+// rechecks whether heap usage has fallen back below threshold.
+const memGuardPollInterval = 500 * time.Millisecond
+
+// ErrDraining is returned by Submit once the pool has been told to Drain:
+// it stops accepting new jobs while letting already-running ones finish.
+var ErrDraining = errors.New("worker: pool is draining")
+
+// ErrIdentityQueueFull is returned by Submit when job.Submitter already has
+// maxQueuedPerIdentity jobs waiting; see New.
+var ErrIdentityQueueFull = errors.New("worker: identity queue is full")
+
+// workerWatchdogInterval is how often the stuck-worker watchdog re-scans
+// worker state; see Pool.watchdog.
+const workerWatchdogInterval = 10 * time.Second
+
+// Runner executes a single job, e.g. shelling out to yt-dlp/gallery-dl.
+type Runner interface {
+	Run(ctx context.Context, job *entity.Job) error
+}
+
+// scaleInterval is how often the pool re-checks its backlog to decide
+// whether to spin up or retire a worker.
+const scaleInterval = 2 * time.Second
+
+// queuedJob pairs a waiting job with when it was queued, for
+// Stats.OldestQueuedAge.
+type queuedJob struct {
+	job      *entity.Job
+	queuedAt time.Time
+}
+
+// Pool runs jobs pulled from its queue across a pool of worker goroutines
+// that scales between minWorkers and maxWorkers based on queue backlog,
+// applying a per-profile KeyedLimiter on top so jobs sharing a cookie/account
+// profile don't all hit the source at once.
+//
+// Jobs are held in a separate FIFO per submitting identity (Job.Submitter,
+// e.g. an API key or source IP) rather than one shared FIFO, and handed out
+// round-robin across identities: a client who enqueues hundreds of URLs at
+// once fills up their own queue, not everyone else's turn. admit is a
+// counting semaphore sized to queueDepth that bounds total queued jobs
+// across every identity combined, the same capacity the single shared
+// channel used to enforce before per-identity fairness was added.
+type Pool struct {
+	runner               Runner
+	profileLimiter       *KeyedLimiter
+	domainLimiter        *DomainLimiter
+	minWorkers           int
+	maxWorkers           int
+	maxQueuedPerIdentity int
+	memGuard             *memguard.Guard
+	log                  *slog.Logger
+
+	admit chan struct{}
+	ready chan struct{}
+
+	draining atomic.Bool
+	active   atomic.Int32
+	nextID   atomic.Int64
+	scaleOut chan struct{}
+	stop     chan struct{}
+
+	stuckTimeout time.Duration
+	stuckGrace   time.Duration
+	recycle      func(job *entity.Job)
+
+	mu          sync.Mutex
+	queues      map[string][]queuedJob // identity -> FIFO of waiting jobs
+	order       []string               // round-robin order of identities with pending jobs
+	queueLen    int                    // total queued jobs across all identities
+	currentJobs map[int64]*entity.Job  // worker id -> job it's currently running, if any
+	workerSince map[int64]time.Time    // worker id -> when it entered its current state (running currentJobs[id], or idle if absent)
+}
+
+// New builds a Pool with the given queue depth, scaling worker goroutines
+// between minWorkers (always running) and maxWorkers (spun up as backlog
+// grows, retired once it drains) based on queue depth, checked every
+// scaleInterval. profileCap bounds how many jobs sharing a Job.Profile may
+// run concurrently (<=0 for unlimited). domainLimiter additionally bounds
+// concurrency per source domain (e.g. youtube.com, instagram.com); pass nil
+// for no domain limits. maxQueuedPerIdentity caps how many jobs a single
+// Job.Submitter may have waiting at once (<=0 for unlimited); see
+// ErrIdentityQueueFull. memGuard, if non-nil, pauses workers from pulling
+// more jobs off the queue while it reports memory pressure; see
+// pkg/memguard and config.Runtime.PauseDequeueThresholdBytes. stuckTimeout
+// flags a worker whose current job has run longer than it as stuck (see
+// Workers); stuckGrace is extra slack on top of that before recycle, if
+// non-nil, is called once to force the attempt to give up its worker slot.
+// stuckTimeout <= 0 disables the watchdog entirely; recycle nil leaves it
+// only flagging, never acting. See config.Job.WorkerStuckTimeout.
+func New(runner Runner, queueDepth, minWorkers, maxWorkers, profileCap int, domainLimiter *DomainLimiter, maxQueuedPerIdentity int, memGuard *memguard.Guard, log *slog.Logger, stuckTimeout, stuckGrace time.Duration, recycle func(job *entity.Job)) *Pool {
+	if minWorkers < 1 {
+		minWorkers = 1
+	}
+
+	if maxWorkers < minWorkers {
+		maxWorkers = minWorkers
+	}
+
+	p := &Pool{
+		runner:               runner,
+		profileLimiter:       NewKeyedLimiter(profileCap),
+		domainLimiter:        domainLimiter,
+		minWorkers:           minWorkers,
+		maxWorkers:           maxWorkers,
+		maxQueuedPerIdentity: maxQueuedPerIdentity,
+		memGuard:             memGuard,
+		log:                  log,
+		stuckTimeout:         stuckTimeout,
+		stuckGrace:           stuckGrace,
+		recycle:              recycle,
+		admit:                make(chan struct{}, queueDepth),
+		ready:                make(chan struct{}, queueDepth),
+		scaleOut:             make(chan struct{}, maxWorkers),
+		stop:                 make(chan struct{}),
+		queues:               make(map[string][]queuedJob),
+		currentJobs:          make(map[int64]*entity.Job, maxWorkers),
+		workerSince:          make(map[int64]time.Time, maxWorkers),
+	}
+
+	for i := 0; i < minWorkers; i++ {
+		p.spawn(context.Background())
+	}
+
+	go p.monitor(context.Background())
+
+	if p.stuckTimeout > 0 {
+		go p.watchdog(context.Background())
+	}
+
+	return p
+}
+
+// spawn starts one more worker goroutine, up to maxWorkers.
+func (p *Pool) spawn(ctx context.Context) {
+	if int(p.active.Load()) >= p.maxWorkers {
+		return
+	}
+
+	p.active.Add(1)
+	go p.loop(ctx, p.nextID.Add(1))
+}
+
+// monitor periodically compares queue backlog against the current worker
+// count, spawning workers up to maxWorkers while there's a backlog and
+// retiring idle ones down to minWorkers once it's gone.
+func (p *Pool) monitor(ctx context.Context) {
+	ticker := time.NewTicker(scaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			backlog := p.queuedCount()
+			active := int(p.active.Load())
+
+			switch {
+			case backlog > 0 && active < p.maxWorkers:
+				p.spawn(ctx)
+			case backlog == 0 && active > p.minWorkers:
+				select {
+				case p.scaleOut <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}
+}
+
+func (p *Pool) queuedCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.queueLen
+}
+
+// Submit enqueues job for processing, blocking once the pool has queueDepth
+// jobs already waiting across every identity. It returns ErrDraining once
+// Drain has been called, or ErrIdentityQueueFull if job.Submitter already
+// has maxQueuedPerIdentity jobs of its own waiting.
+func (p *Pool) Submit(job *entity.Job) error {
+	if p.draining.Load() {
+		return ErrDraining
+	}
+
+	identity := job.Submitter
+
+	p.mu.Lock()
+	if p.maxQueuedPerIdentity > 0 && len(p.queues[identity]) >= p.maxQueuedPerIdentity {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: %q has %d jobs queued", ErrIdentityQueueFull, identity, p.maxQueuedPerIdentity)
+	}
+	p.mu.Unlock()
+
+	p.admit <- struct{}{}
+
+	p.mu.Lock()
+	if _, queued := p.queues[identity]; !queued {
+		p.order = append(p.order, identity)
+	}
+	p.queues[identity] = append(p.queues[identity], queuedJob{job: job, queuedAt: time.Now()})
+	p.queueLen++
+	p.mu.Unlock()
+
+	p.ready <- struct{}{}
+
+	return nil
+}
+
+// next pops the next job to run, rotating round-robin across identities: the
+// identity at the head of order goes to the back of the line if it still has
+// jobs waiting, so no single identity can monopolize consecutive turns.
+func (p *Pool) next() (*entity.Job, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.order) == 0 {
+		return nil, false
+	}
+
+	identity := p.order[0]
+	p.order = p.order[1:]
+
+	q := p.queues[identity]
+	job := q[0].job
+	q = q[1:]
+
+	if len(q) > 0 {
+		p.queues[identity] = q
+		p.order = append(p.order, identity)
+	} else {
+		delete(p.queues, identity)
+	}
+
+	p.queueLen--
+	<-p.admit
+
+	return job, true
+}
+
+// Drain stops the pool from accepting new jobs via Submit; jobs already
+// queued or running are unaffected and continue to completion.
+func (p *Pool) Drain() {
+	p.draining.Store(true)
+}
+
+// Draining reports whether Drain has been called.
+func (p *Pool) Draining() bool {
+	return p.draining.Load()
+}
+
+// Shutdown stops the pool accepting new jobs and waits for already-running
+// jobs to finish, up to ctx's deadline. It returns the jobs still sitting
+// in the queue (i.e. never picked up by a worker) so the caller can persist
+// them and requeue them on the next startup; see SaveQueue.
+func (p *Pool) Shutdown(ctx context.Context) []*entity.Job {
+	p.Drain()
+	close(p.stop)
+
+	var unfinished []*entity.Job
+
+	p.mu.Lock()
+	for _, identity := range p.order {
+		for _, qj := range p.queues[identity] {
+			unfinished = append(unfinished, qj.job)
+		}
+	}
+	p.queues = make(map[string][]queuedJob)
+	p.order = nil
+	p.queueLen = 0
+	p.mu.Unlock()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for p.runningCount() > 0 {
+		select {
+		case <-ctx.Done():
+			return unfinished
+		case <-ticker.C:
+		}
+	}
+
+	return unfinished
+}
+
+func (p *Pool) runningCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.currentJobs)
+}
+
+// Stats is a point-in-time snapshot of the pool's load, used by
+// diagnostics (see the admin support-bundle and queue introspection
+// endpoints).
+type Stats struct {
+	// MinWorkers/MaxWorkers are the configured scaling bounds; ActiveWorkers
+	// is how many worker goroutines are running right now, somewhere in
+	// between depending on backlog.
+	MinWorkers    int `json:"min_workers"`
+	MaxWorkers    int `json:"max_workers"`
+	ActiveWorkers int `json:"active_workers"`
+	QueueDepth    int `json:"queue_depth"`
+	QueueLen      int `json:"queue_len"`
+	// Draining reports whether the pool has stopped accepting new jobs.
+	Draining bool `json:"draining"`
+	// OldestQueuedAge is how long the longest-waiting queued job has been
+	// waiting, 0 if the queue is empty.
+	OldestQueuedAge time.Duration `json:"oldest_queued_age"`
+	// QueuedIdentities is how many distinct submitters currently have at
+	// least one job waiting, for spotting an unfair backlog at a glance.
+	QueuedIdentities int `json:"queued_identities"`
+	// WorkerJobs lists the jobs currently running, one per busy worker; a
+	// pool with idle workers has fewer entries than ActiveWorkers.
+	WorkerJobs []entity.Job `json:"worker_jobs"`
+}
+
+// Stats returns a snapshot of the pool's current load.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := Stats{
+		MinWorkers:       p.minWorkers,
+		MaxWorkers:       p.maxWorkers,
+		ActiveWorkers:    int(p.active.Load()),
+		QueueDepth:       cap(p.admit),
+		QueueLen:         p.queueLen,
+		Draining:         p.draining.Load(),
+		QueuedIdentities: len(p.order),
+	}
+
+	var oldest time.Time
+
+	for _, q := range p.queues {
+		if len(q) == 0 {
+			continue
+		}
+
+		if oldest.IsZero() || q[0].queuedAt.Before(oldest) {
+			oldest = q[0].queuedAt
+		}
+	}
+
+	if !oldest.IsZero() {
+		stats.OldestQueuedAge = time.Since(oldest)
+	}
+
+	for _, job := range p.currentJobs {
+		stats.WorkerJobs = append(stats.WorkerJobs, *job)
+	}
+
+	return stats
+}
+
+// WorkerInfo is a point-in-time snapshot of one worker goroutine, for
+// diagnosing the "queue stops draining" class of incident; see Pool.Workers
+// and GET /v1/admin/workers.
+type WorkerInfo struct {
+	ID int64 `json:"id"`
+	// Job is the job this worker is currently running, nil while idle.
+	Job *entity.Job `json:"job,omitempty"`
+	// Since is when the worker entered its current state (started running
+	// Job, or went idle if Job is nil).
+	Since time.Time `json:"since"`
+	// StuckFor is how long Job has been running past stuckTimeout, 0 if
+	// the worker is idle or hasn't crossed stuckTimeout yet; see
+	// config.Job.WorkerStuckTimeout.
+	StuckFor time.Duration `json:"stuck_for,omitempty"`
+}
+
+// Workers returns a snapshot of every live worker goroutine, sorted by ID.
+func (p *Pool) Workers() []WorkerInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	infos := make([]WorkerInfo, 0, len(p.workerSince))
+
+	for id, since := range p.workerSince {
+		info := WorkerInfo{ID: id, Since: since}
+
+		if job, running := p.currentJobs[id]; running {
+			j := *job
+			info.Job = &j
+
+			if p.stuckTimeout > 0 {
+				if runningFor := time.Since(since); runningFor > p.stuckTimeout {
+					info.StuckFor = runningFor - p.stuckTimeout
+				}
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+
+	return infos
+}
+
+// watchdog periodically scans for workers whose current job has run beyond
+// stuckTimeout+stuckGrace and, if recycle is set, calls it once per such
+// occurrence to force the attempt to give up its worker slot (the job's own
+// retry policy, not this watchdog, decides whether it runs again). It
+// blocks until the pool is stopped; only started when stuckTimeout > 0, see
+// New.
+func (p *Pool) watchdog(ctx context.Context) {
+	ticker := time.NewTicker(workerWatchdogInterval)
+	defer ticker.Stop()
+
+	// recycled remembers, per worker ID, the workerSince value already
+	// acted on, so a job stuck across several ticks is only recycled once;
+	// it's cleared once that worker moves on to a different state.
+	recycled := make(map[int64]time.Time)
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.scanForStuckWorkers(recycled)
+		}
+	}
+}
+
+func (p *Pool) scanForStuckWorkers(recycled map[int64]time.Time) {
+	var stuck []*entity.Job
+
+	p.mu.Lock()
+
+	for id, since := range p.workerSince {
+		job, running := p.currentJobs[id]
+		if !running {
+			delete(recycled, id)
+			continue
+		}
+
+		if time.Since(since) < p.stuckTimeout+p.stuckGrace {
+			continue
+		}
+
+		if recycled[id].Equal(since) {
+			continue
+		}
+
+		recycled[id] = since
+		stuck = append(stuck, job)
+	}
+
+	p.mu.Unlock()
+
+	for _, job := range stuck {
+		p.log.Warn("worker watchdog: job stuck past timeout+grace", "job_id", job.ID, "url", job.URL)
+
+		if p.recycle != nil {
+			p.recycle(job)
+		}
+	}
+}
+
+// loop runs worker workerID until the pool is stopped or it's told to
+// retire via scaleOut while idle, decrementing active either way.
+func (p *Pool) loop(ctx context.Context, workerID int64) {
+	ctx = logger.WithWorkerID(ctx, int(workerID))
+
+	p.mu.Lock()
+	p.workerSince[workerID] = time.Now()
+	p.mu.Unlock()
+
+	defer func() {
+		p.active.Add(-1)
+
+		p.mu.Lock()
+		delete(p.workerSince, workerID)
+		p.mu.Unlock()
+	}()
+
+	for {
+		for p.memGuard.OverLimit() {
+			select {
+			case <-p.stop:
+				return
+			case <-time.After(memGuardPollInterval):
+			}
+		}
+
+		var job *entity.Job
+
+		select {
+		case <-p.ready:
+			j, ok := p.next()
+			if !ok {
+				continue
+			}
+
+			job = j
+		case <-p.scaleOut:
+			return
+		case <-p.stop:
+			return
+		}
+
+		p.mu.Lock()
+		p.currentJobs[workerID] = job
+		p.workerSince[workerID] = time.Now()
+		p.mu.Unlock()
+
+		// A job can be tombstoned by JobService.Cancel while it's still
+		// sitting in the queue, setting this same *entity.Job's Status
+		// before a worker ever picks it up; skip running it rather than
+		// starting a download that's already been called off.
+		if job.Status() == entity.JobStatusCancelled {
+			p.mu.Lock()
+			delete(p.currentJobs, workerID)
+			p.workerSince[workerID] = time.Now()
+			p.mu.Unlock()
+
+			continue
+		}
+
+		p.profileLimiter.Acquire(job.Profile)
+		p.domainLimiter.Acquire(job.URL)
+
+		if err := p.runner.Run(ctx, job); err != nil {
+			p.log.ErrorContext(logger.WithJobID(ctx, job.ID.String()), "job failed", "error", err)
+		}
+
+		p.domainLimiter.Release(job.URL)
+		p.profileLimiter.Release(job.Profile)
+
+		p.mu.Lock()
+		delete(p.currentJobs, workerID)
+		p.workerSince[workerID] = time.Now()
+		p.mu.Unlock()
+	}
+}
@@ -0,0 +1,152 @@
+// Package ws implements just enough of RFC 6455 to serve a JSON
+// request/response WebSocket API without pulling in a third-party
+// dependency: handshake, text-frame read/write, and close handling.
+// Binary frames are read like text frames and never written;
+// fragmentation and compression extensions are not supported, which is
+// fine for the small control messages this API exchanges.
+package ws
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Conn is a minimal text-frame WebSocket connection.
+type Conn struct {
+	rw  *bufio.ReadWriter
+	net net.Conn
+}
+
+// ErrClosed is returned by ReadMessage once the peer has sent a close frame.
+var ErrClosed = errors.New("ws: connection closed")
+
+// ReadMessage blocks for the next text message, unmasking it per RFC 6455
+// (client-to-server frames are always masked).
+func (c *Conn) ReadMessage() ([]byte, error) {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opText, opBinary:
+			return payload, nil
+		case opClose:
+			_ = c.writeFrame(opClose, nil)
+			return nil, ErrClosed
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong, opContinuation:
+			// ignore
+		default:
+			return nil, fmt.Errorf("ws: unsupported opcode %#x", opcode)
+		}
+	}
+}
+
+// WriteMessage sends payload as a single unmasked text frame (server
+// frames are never masked, per RFC 6455).
+func (c *Conn) WriteMessage(payload []byte) error {
+	return c.writeFrame(opText, payload)
+}
+
+// Close sends a close frame and closes the underlying connection.
+func (c *Conn) Close() error {
+	_ = c.writeFrame(opClose, nil)
+	return c.net.Close()
+}
+
+func (c *Conn) readFrame() (opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.rw, header); err != nil {
+		return 0, nil, err
+	}
+
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.rw, ext); err != nil {
+			return 0, nil, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.rw, ext); err != nil {
+			return 0, nil, err
+		}
+
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.rw, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode} // FIN=1, no extension bits
+
+	n := len(payload)
+
+	switch {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 65535:
+		ext := make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(n))
+		header = append(header, 126)
+		header = append(header, ext...)
+	default:
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(n))
+		header = append(header, 127)
+		header = append(header, ext...)
+	}
+
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+
+	return c.rw.Flush()
+}
@@ -0,0 +1,59 @@
+package ws
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// magicGUID is the fixed RFC 6455 handshake GUID.
+const magicGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Upgrade performs the WebSocket handshake and hijacks the underlying
+// connection, returning a Conn for the caller to read/write frames on.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return nil, fmt.Errorf("ws: missing Upgrade: websocket header")
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("ws: missing Sec-WebSocket-Key header")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("ws: response writer doesn't support hijacking")
+	}
+
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijack: %w", err)
+	}
+
+	accept := acceptKey(key)
+
+	if _, err := fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", accept); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("write handshake: %w", err)
+	}
+
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("flush handshake: %w", err)
+	}
+
+	return &Conn{rw: rw, net: netConn}, nil
+}
+
+func acceptKey(clientKey string) string {
+	h := sha1.New()
+	h.Write([]byte(clientKey + magicGUID))
+
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
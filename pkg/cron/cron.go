@@ -0,0 +1,112 @@
+// Package cron parses standard 5-field cron expressions ("minute hour
+// day-of-month month day-of-week") and computes their next firing time. A
+// minimal hand-rolled implementation, in keeping with this module's
+// near-zero third-party dependency footprint; it supports "*", single
+// values and comma-separated lists per field, which covers the scheduler's
+// needs without pulling in a cron library.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minutes map[int]bool
+	hours   map[int]bool
+	doms    map[int]bool
+	months  map[int]bool
+	dows    map[int]bool
+	spec    string
+}
+
+// Parse parses a standard 5-field cron expression.
+func Parse(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields, got %d in %q", len(fields), spec)
+	}
+
+	minutes, err := parseField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("cron: minute field: %w", err)
+	}
+
+	hours, err := parseField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("cron: hour field: %w", err)
+	}
+
+	doms, err := parseField(fields[2], 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("cron: day-of-month field: %w", err)
+	}
+
+	months, err := parseField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("cron: month field: %w", err)
+	}
+
+	dows, err := parseField(fields[4], 0, 6)
+	if err != nil {
+		return nil, fmt.Errorf("cron: day-of-week field: %w", err)
+	}
+
+	return &Schedule{minutes: minutes, hours: hours, doms: doms, months: months, dows: dows, spec: spec}, nil
+}
+
+// String returns the original cron expression.
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time strictly after after that matches s, scanning
+// minute by minute up to 4 years out (cron's granularity is a minute, so a
+// finer search isn't meaningful).
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(4, 0, 0)
+
+	for t.Before(limit) {
+		if s.months[int(t.Month())] && s.doms[t.Day()] && s.dows[int(t.Weekday())] &&
+			s.hours[t.Hour()] && s.minutes[t.Minute()] {
+			return t
+		}
+
+		t = t.Add(time.Minute)
+	}
+
+	return time.Time{}
+}
+
+// parseField parses one "*" | "N" | "N,M,..." cron field into a set of the
+// matching values in [min, max].
+func parseField(field string, min, max int) (map[int]bool, error) {
+	set := make(map[int]bool)
+
+	if field == "*" {
+		for v := min; v <= max; v++ {
+			set[v] = true
+		}
+
+		return set, nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", part)
+		}
+
+		if v < min || v > max {
+			return nil, fmt.Errorf("value %d out of range [%d, %d]", v, min, max)
+		}
+
+		set[v] = true
+	}
+
+	return set, nil
+}
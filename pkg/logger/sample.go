@@ -0,0 +1,134 @@
+// Package logger provides slog.Handler wrappers shared across the
+// application.
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ParseSampleRates parses "event=every" pairs (e.g. "progress=50") into the
+// map NewSamplingHandler expects, skipping malformed entries since a bad
+// config value shouldn't take the server down.
+func ParseSampleRates(pairs []string) map[string]int {
+	rates := make(map[string]int, len(pairs))
+
+	for _, pair := range pairs {
+		event, everyStr, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+
+		every, err := strconv.Atoi(strings.TrimSpace(everyStr))
+		if err != nil {
+			continue
+		}
+
+		rates[strings.TrimSpace(event)] = every
+	}
+
+	return rates
+}
+
+// SamplingHandler wraps a slog.Handler, keeping only 1 of every N records
+// for high-cardinality, repetitive events (e.g. per-job download progress
+// lines) so debug logging stays usable in production instead of drowning
+// out everything else. Records are keyed by their "event" attribute; rates
+// are configured per event, with a default applied to events with no
+// specific rate.
+type SamplingHandler struct {
+	next         slog.Handler
+	rates        map[string]int
+	defaultEvery int
+
+	// state is shared across handlers derived via WithAttrs/WithGroup so a
+	// logger's sample counts stay consistent however it's scoped.
+	state *sampleState
+}
+
+type sampleState struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewSamplingHandler builds a SamplingHandler. rates maps an "event"
+// attribute value to "keep 1 in every N" (<=1 keeps all of them);
+// defaultEvery applies to events absent from rates.
+func NewSamplingHandler(next slog.Handler, rates map[string]int, defaultEvery int) *SamplingHandler {
+	return &SamplingHandler{
+		next:         next,
+		rates:        rates,
+		defaultEvery: defaultEvery,
+		state:        &sampleState{counts: make(map[string]int)},
+	}
+}
+
+// Enabled reports whether the wrapped handler would handle records at
+// level; sampling happens in Handle, after the level check.
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle drops the record if it falls outside this event's sample rate,
+// otherwise passes it through to the wrapped handler unchanged.
+func (h *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	event, ok := recordEvent(record)
+	if !ok {
+		return h.next.Handle(ctx, record)
+	}
+
+	every, ok := h.rates[event]
+	if !ok {
+		every = h.defaultEvery
+	}
+
+	if every <= 1 {
+		return h.next.Handle(ctx, record)
+	}
+
+	h.state.mu.Lock()
+	h.state.counts[event]++
+	n := h.state.counts[event]
+	h.state.mu.Unlock()
+
+	if n%every != 1 {
+		return nil
+	}
+
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a new SamplingHandler wrapping the result of applying
+// attrs to the underlying handler, preserving sample counts.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), rates: h.rates, defaultEvery: h.defaultEvery, state: h.state}
+}
+
+// WithGroup returns a new SamplingHandler wrapping the result of applying
+// the group to the underlying handler, preserving sample counts.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), rates: h.rates, defaultEvery: h.defaultEvery, state: h.state}
+}
+
+func recordEvent(record slog.Record) (string, bool) {
+	var (
+		event string
+		found bool
+	)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "event" {
+			event = attr.Value.String()
+			found = true
+
+			return false
+		}
+
+		return true
+	})
+
+	return event, found
+}
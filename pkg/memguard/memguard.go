@@ -0,0 +1,37 @@
+// Package memguard reports whether process heap usage has crossed a
+// configured threshold, so a high-throughput component can pause pulling
+// more work until garbage collection catches up instead of risking an OOM
+// kill.
+package memguard
+
+import "runtime"
+
+// Guard traps heap usage against a fixed threshold.
+type Guard struct {
+	thresholdBytes uint64
+}
+
+// New builds a Guard that trips once the runtime reports more than
+// thresholdBytes of heap in use. thresholdBytes <= 0 disables it: OverLimit
+// always reports false.
+func New(thresholdBytes int64) *Guard {
+	if thresholdBytes <= 0 {
+		return nil
+	}
+
+	return &Guard{thresholdBytes: uint64(thresholdBytes)}
+}
+
+// OverLimit reports whether current heap usage exceeds the configured
+// threshold. A nil Guard never trips, so callers can hold one unconditionally
+// and skip a separate "is this enabled" check.
+func (g *Guard) OverLimit() bool {
+	if g == nil {
+		return false
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return stats.HeapAlloc > g.thresholdBytes
+}
@@ -0,0 +1,54 @@
+// Package privacy redacts personal data (source URLs, which can embed
+// tokens or usernames in their path/query) from text before it reaches
+// logs or API responses.
+package privacy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// urlPattern matches http(s) URLs embedded in free text, e.g. a
+// downloader's error message or stdout line that echoes the source URL.
+var urlPattern = regexp.MustCompile(`https?://\S+`)
+
+// RedactURL returns raw with its path and query replaced by a short hash
+// and any fragment dropped, keeping the scheme and host intact since those are useful for
+// triage without identifying the specific resource. salt is mixed into the
+// hash so it can't be reversed via a dictionary of known paths; it should
+// be a random, per-deployment value.
+func RedactURL(raw, salt string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "[redacted:" + hash(raw, salt) + "]"
+	}
+
+	rest := u.Path
+	if u.RawQuery != "" {
+		rest += "?" + u.RawQuery
+	}
+
+	if rest == "" {
+		return u.Scheme + "://" + u.Host
+	}
+
+	return fmt.Sprintf("%s://%s/[redacted:%s]", u.Scheme, u.Host, hash(rest, salt))
+}
+
+// RedactText replaces every http(s) URL found in s with its RedactURL
+// form, leaving the rest of the text untouched. Used to scrub error
+// messages and captured downloader output that may embed a source URL.
+func RedactText(s, salt string) string {
+	return urlPattern.ReplaceAllStringFunc(s, func(u string) string {
+		return RedactURL(u, salt)
+	})
+}
+
+func hash(s, salt string) string {
+	sum := sha256.Sum256([]byte(salt + s))
+
+	return hex.EncodeToString(sum[:])[:8]
+}
@@ -0,0 +1,144 @@
+// Package ratelimit implements a simple per-key token-bucket limiter.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/evan-sm/daunrodo/pkg/clock"
+)
+
+// Limiter caps how often a given key (e.g. an API key or client IP) may act,
+// using an independent token bucket per key. A key that hasn't been seen in
+// a while is forgotten; see Limiter.sweep.
+type Limiter struct {
+	ratePerMin float64
+	burst      float64
+	clock      clock.Clock
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// New builds a Limiter allowing ratePerMinute requests per minute per key,
+// with burst additional requests allowed instantly before the steady-state
+// rate kicks in. ratePerMinute <= 0 disables limiting (Allow always true).
+func New(ratePerMinute, burst int, clk clock.Clock) *Limiter {
+	return &Limiter{
+		ratePerMin: float64(ratePerMinute),
+		burst:      float64(burst),
+		clock:      clk,
+		buckets:    make(map[string]*bucket),
+	}
+}
+
+// Allow reports whether key may act now, consuming a token if so. When
+// denied, retryAfter estimates how long until the next token is available.
+func (l *Limiter) Allow(key string) (allowed bool, retryAfter time.Duration) {
+	if l.ratePerMin <= 0 {
+		return true, 0
+	}
+
+	now := l.clock.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastRefill: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastRefill)
+	b.tokens = min(l.burst, b.tokens+elapsed.Minutes()*l.ratePerMin)
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		shortfall := 1 - b.tokens
+		retryAfter = time.Duration(shortfall / l.ratePerMin * float64(time.Minute))
+
+		return false, retryAfter
+	}
+
+	b.tokens--
+
+	return true, 0
+}
+
+// ByteBucket is a token bucket denominated in bytes/sec rather than
+// actions/minute, used to throttle an io.Writer's throughput instead of
+// gating discrete requests like Limiter does.
+type ByteBucket struct {
+	ratePerSec float64
+	burst      float64
+	clock      clock.Clock
+
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewByteBucket builds a ByteBucket allowing ratePerSec bytes/sec
+// sustained, with burst additional bytes available instantly. ratePerSec
+// <= 0 disables limiting entirely: Take returns immediately.
+func NewByteBucket(ratePerSec, burst int, clk clock.Clock) *ByteBucket {
+	return &ByteBucket{
+		ratePerSec: float64(ratePerSec),
+		burst:      float64(burst),
+		clock:      clk,
+		tokens:     float64(burst),
+		lastRefill: clk.Now(),
+	}
+}
+
+// Take blocks until n bytes' worth of tokens are available, or until ctx is
+// cancelled. It returns immediately (consuming nothing) if the bucket was
+// built with ratePerSec <= 0.
+func (b *ByteBucket) Take(ctx context.Context, n int) error {
+	if b.ratePerSec <= 0 {
+		return nil
+	}
+
+	for {
+		wait, ok := b.take(n)
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// take refills the bucket and attempts to consume n tokens, reporting how
+// long the caller should wait before trying again if it couldn't.
+func (b *ByteBucket) take(n int) (wait time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	elapsed := now.Sub(b.lastRefill)
+	b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.ratePerSec)
+	b.lastRefill = now
+
+	need := float64(n)
+	if b.tokens >= need {
+		b.tokens -= need
+
+		return 0, true
+	}
+
+	shortfall := need - b.tokens
+
+	return time.Duration(shortfall / b.ratePerSec * float64(time.Second)), false
+}
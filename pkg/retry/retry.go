@@ -0,0 +1,65 @@
+// Package retry implements a small exponential-backoff retry helper shared
+// by services that call out to unreliable external tools or networks.
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy configures retry behavior: up to MaxAttempts total tries (the first
+// try plus MaxAttempts-1 retries), with the delay between attempts doubling
+// from Base each time, jittered by up to +/-50% so many callers retrying the
+// same failure don't all wake up and retry in lockstep.
+type Policy struct {
+	MaxAttempts int
+	Base        time.Duration
+	// Retryable classifies an error returned by Do's fn as worth retrying;
+	// nil (the default) retries every error, matching every caller's
+	// behavior before this field existed. Return false for an error Do
+	// should give up on immediately, e.g. a permanent 4xx response.
+	Retryable func(error) bool
+}
+
+// Delay returns the jittered backoff delay before attempt (1-indexed: the
+// delay before the 2nd attempt averages Base, before the 3rd averages
+// 2*Base, and so on).
+func (p Policy) Delay(attempt int) time.Duration {
+	if attempt <= 1 {
+		return 0
+	}
+
+	base := p.Base << (attempt - 2)
+	jitter := time.Duration(rand.Int64N(int64(base))) - base/2
+
+	return base + jitter
+}
+
+// Do calls fn until it succeeds, p.MaxAttempts is reached, or fn's error is
+// classified non-retryable by p.Retryable, sleeping p.Delay(attempt) between
+// tries. It returns fn's last error if every attempt fails, or ctx.Err() if
+// ctx is cancelled while waiting.
+func Do(ctx context.Context, p Policy, fn func(attempt int) error) error {
+	var err error
+
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(p.Delay(attempt)):
+			}
+		}
+
+		if err = fn(attempt); err == nil {
+			return nil
+		}
+
+		if p.Retryable != nil && !p.Retryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
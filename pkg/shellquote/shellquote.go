@@ -0,0 +1,30 @@
+// Package shellquote quotes strings for safe display as a POSIX shell
+// command line, e.g. for logging a reproducible external tool invocation.
+package shellquote
+
+import "strings"
+
+const specialChars = " \t\n'\"\\$`*?[]{}()<>|;&~!#"
+
+// Join quotes each of args as needed and joins them with spaces, producing
+// a string that can be pasted into a shell to reproduce the command.
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quote(a)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	if !strings.ContainsAny(s, specialChars) {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}